@@ -3,6 +3,8 @@ package aprs
 import (
 	"bytes"
 	"fmt"
+	"packetmap/packet"
+	"strconv"
 	"strings"
 )
 
@@ -27,15 +29,39 @@ func parseAddressText(callStr string) (string, error) {
 
 }
 
+// header holds the address fields we keep from a frame.
+type header struct {
+	Source      string              // Source callsign-SSID
+	Destination string              // Destination callsign-SSID (carries Mic-E latitude)
+	Path        []packet.Digipeater // Digipeaters in order, with has-been-repeated flags
+	QConstruct  string              // APRS-IS q-construct, e.g. "qAR" (text lines only)
+	IGate       string              // Callsign following the q-construct
+}
+
+// isTNC2 reports whether frame looks like a TNC2 text line
+// (CALL>DEST,PATH:payload) rather than raw AX.25. AX.25 addresses are
+// shifted left one bit, so they never contain a '>' before the payload.
+func isTNC2(frame []byte) bool {
+	for i := 0; i < len(frame) && i <= 9; i++ {
+		c := frame[i]
+		if c == '>' {
+			return i > 0
+		}
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return false
+}
+
 // findPayload searches the frame for the APRS payload.
 // MODIFIED: It now handles both raw AX.25 bytes and APRS-IS text lines.
-func findPayload(frame []byte) (string, []byte, error) {
+func findPayload(frame []byte) (header, []byte, error) {
 	// Attempt to find standard APRS-IS text format first: CALL>DEST,PATH:payload
 	frameStr := string(frame) // Work with strings for text parsing
 	separatorIndex := strings.Index(frameStr, ":")
-	if separatorIndex == -1 {
-		// No colon found, maybe it's a raw AX.25 frame? Or malformed?
-		// Fallback to trying AX.25 logic (less likely for APRS-IS)
+	if separatorIndex == -1 || !isTNC2(frame) {
+		// Not a text line, so it should be a raw AX.25 frame (from KISS)
 		return findPayloadAX25(frame)
 	}
 
@@ -45,29 +71,82 @@ func findPayload(frame []byte) (string, []byte, error) {
 	// Extract source callsign (part before '>')
 	callEndIndex := strings.Index(headerPart, ">")
 	if callEndIndex == -1 {
-		return "", nil, fmt.Errorf("no source callsign separator '>' found in header: %s", headerPart)
+		return header{}, nil, fmt.Errorf("no source callsign separator '>' found in header: %s", headerPart)
 	}
 	srcCallStr := headerPart[:callEndIndex]
 
+	// Destination is everything up to the first path entry
+	destCallStr := headerPart[callEndIndex+1:]
+	hdr := header{Source: srcCallStr}
+	if pathIndex := strings.Index(destCallStr, ","); pathIndex != -1 {
+		parseTextPath(&hdr, strings.Split(destCallStr[pathIndex+1:], ","))
+		destCallStr = destCallStr[:pathIndex]
+	}
+	hdr.Destination = destCallStr
+
 	// Basic validation of source call
 	// We don't need full AX.25 validation here
 	if len(srcCallStr) == 0 || len(srcCallStr) > 9 { // APRS callsigns can be up to 9 chars
-		return "", nil, fmt.Errorf("invalid source callsign format: %s", srcCallStr)
+		return header{}, nil, fmt.Errorf("invalid source callsign format: %s", srcCallStr)
 	}
 
-	return srcCallStr, []byte(payload), nil
+	return hdr, []byte(payload), nil
+}
+
+// parseTextPath fills in the digipeater path and q-construct from the
+// comma-separated path of a TNC2 header.
+func parseTextPath(hdr *header, entries []string) {
+	lastRepeated := -1
+	for i, entry := range entries {
+		// APRS-IS: qAx then the igate (or server) that injected it
+		if len(entry) == 3 && entry[0] == 'q' && entry[1] == 'A' {
+			hdr.QConstruct = entry
+			if i+1 < len(entries) {
+				hdr.IGate = entries[i+1]
+			}
+			break
+		}
+
+		call := strings.TrimSuffix(entry, "*")
+		if call != entry {
+			lastRepeated = len(hdr.Path)
+		}
+		hdr.Path = append(hdr.Path, packet.Digipeater{Callsign: call})
+	}
+
+	// TNC2 only marks the last digipeater used; everything before it
+	// has been repeated too
+	for i := 0; i <= lastRepeated; i++ {
+		hdr.Path[i].Repeated = true
+	}
 }
 
 // findPayloadAX25 handles the original logic for raw AX.25 frames (from KISS).
-func findPayloadAX25(frame []byte) (string, []byte, error) {
+func findPayloadAX25(frame []byte) (header, []byte, error) {
+	hdr, payload, err := decodeAX25(frame)
+	if err != nil {
+		return header{}, nil, err
+	}
+
+	// Raw AX.25 might still have TNC2 prefix sometimes from digipeaters, keep the check.
+	return hdr, payload[tnc2PrefixLen(payload):], nil
+}
+
+// decodeAX25 splits a raw AX.25 UI frame into its address header and
+// its information field, exactly as sent.
+func decodeAX25(frame []byte) (header, []byte, error) {
 	if len(frame) < 16 { // Min size: Dest(7) + Src(7) + Ctrl(1) + PID(1)
-		return "", nil, fmt.Errorf("frame too short for AX.25")
+		return header{}, nil, fmt.Errorf("frame too short for AX.25")
 	}
 
 	// AX.25 Address parsing logic (requires byte manipulation)
+	destCall, _, err := parseAddressBytes(frame[0:7])
+	if err != nil {
+		return header{}, nil, fmt.Errorf("invalid AX.25 destination address: %w", err)
+	}
 	srcCall, _, err := parseAddressBytes(frame[7:14])
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid AX.25 source address: %w", err)
+		return header{}, nil, fmt.Errorf("invalid AX.25 source address: %w", err)
 	}
 
 	// Find end of address path (LSB check)
@@ -78,21 +157,34 @@ func findPayloadAX25(frame []byte) (string, []byte, error) {
 		}
 		// Sanity check: prevent infinite loop if LSB is never set
 		if addrEndIndex+7 > len(frame)+14 { // Allow some room but prevent going way too far
-			return "", nil, fmt.Errorf("could not find end of AX.25 address path (LSB never set?)")
+			return header{}, nil, fmt.Errorf("could not find end of AX.25 address path (LSB never set?)")
 		}
 		addrEndIndex += 7
 	}
 
 
 	if addrEndIndex+2 > len(frame) {
-		return "", nil, fmt.Errorf("could not find AX.25 control/PID fields after address path")
+		return header{}, nil, fmt.Errorf("could not find AX.25 control/PID fields after address path")
+	}
+
+	// Digipeater addresses sit between the source and the control field
+	var path []packet.Digipeater
+	for i := 14; i+7 <= addrEndIndex; i += 7 {
+		digi, ssidByte, err := parseAddressBytes(frame[i : i+7])
+		if err != nil {
+			return header{}, nil, fmt.Errorf("invalid AX.25 digipeater address: %w", err)
+		}
+		path = append(path, packet.Digipeater{
+			Callsign: digi,
+			Repeated: ssidByte&0x80 != 0, // H-bit
+		})
 	}
 
 	controlField := frame[addrEndIndex]
 	pidField := frame[addrEndIndex+1]
 
 	if controlField != controlUI {
-		return "", nil, fmt.Errorf("not a UI frame (control: 0x%02X)", controlField)
+		return header{}, nil, fmt.Errorf("not a UI frame (control: 0x%02X)", controlField)
 	}
 
 	if pidField != pidNoLayer3 {
@@ -102,16 +194,36 @@ func findPayloadAX25(frame []byte) (string, []byte, error) {
 
 	payload := frame[addrEndIndex+2:]
 
-	// Raw AX.25 might still have TNC2 prefix sometimes from digipeaters, keep the check
-	if colonIndex := bytes.IndexByte(payload, ':'); colonIndex != -1 {
-		// Only strip if it looks like CALL>PATH: prefix
-		if bytes.IndexByte(payload[:colonIndex], '>') != -1 {
-			payload = payload[colonIndex+1:]
-		}
-	}
+	return header{Source: srcCall, Destination: destCall, Path: path}, payload, nil
+}
 
+// dataTypeIDs are the APRS data type identifiers a payload can start
+// with. A payload that starts with one is never a TNC2 header, even if
+// it carries one (third-party '}' packets do).
+const dataTypeIDs = "`'!=/@;):>T$_}"
 
-	return srcCall, payload, nil
+// tnc2PrefixLen returns the length of a CALL>DEST,PATH: header at the
+// start of payload, or 0 if there isn't one. A '>' and ':' further into
+// the text (a Mic-E car symbol, a time in a comment) don't count: the
+// '>' must come early with no ':' before it, and the header can't
+// contain spaces.
+func tnc2PrefixLen(payload []byte) int {
+	if len(payload) == 0 || strings.IndexByte(dataTypeIDs, payload[0]) != -1 {
+		return 0
+	}
+	if !isTNC2(payload) {
+		return 0
+	}
+	colonIndex := bytes.IndexByte(payload, ':')
+	if colonIndex < bytes.IndexByte(payload, '>') {
+		return 0
+	}
+	for _, c := range payload[:colonIndex] {
+		if c <= ' ' || c > '~' {
+			return 0
+		}
+	}
+	return colonIndex + 1
 }
 
 // parseAddressBytes decodes a 7-byte AX.25 address field.
@@ -152,4 +264,84 @@ func parseAddressBytes(addr []byte) (string, byte, error) {
 		return fmt.Sprintf("%s-%d", callStr, ssid), ssidByte, nil
 	}
 	return callStr, ssidByte, nil
-}
\ No newline at end of file
+}
+
+// BuildUIFrame encodes an AX.25 UI frame (control 0x03, PID 0xF0) for
+// transmission. Path entries are digipeater callsigns such as "WIDE1-1";
+// a trailing '*' marks one as already repeated.
+func BuildUIFrame(source, destination string, path []string, payload []byte) ([]byte, error) {
+	if len(path) > 8 {
+		return nil, fmt.Errorf("too many digipeaters in path: %d (max 8)", len(path))
+	}
+
+	var frame bytes.Buffer
+
+	// The destination carries the command bit, the source doesn't (AX.25 v2)
+	addr, err := encodeAddress(destination, 0x80, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination address: %w", err)
+	}
+	frame.Write(addr)
+
+	addr, err = encodeAddress(source, 0, len(path) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source address: %w", err)
+	}
+	frame.Write(addr)
+
+	for i, digi := range path {
+		var hBit byte
+		call := strings.TrimSuffix(digi, "*")
+		if call != digi {
+			hBit = 0x80
+		}
+		addr, err = encodeAddress(call, hBit, i == len(path)-1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digipeater address: %w", err)
+		}
+		frame.Write(addr)
+	}
+
+	frame.WriteByte(controlUI)
+	frame.WriteByte(pidNoLayer3)
+	frame.Write(payload)
+	return frame.Bytes(), nil
+}
+
+// encodeAddress encodes CALL-SSID as a 7-byte AX.25 address field.
+// flag is the command/has-been-repeated bit; last sets the
+// end-of-address bit.
+func encodeAddress(address string, flag byte, last bool) ([]byte, error) {
+	call, ssidStr, hasSSID := strings.Cut(strings.ToUpper(address), "-")
+	if len(call) == 0 || len(call) > 6 {
+		return nil, fmt.Errorf("callsign must be 1-6 characters: %q", address)
+	}
+
+	var ssid int
+	if hasSSID {
+		n, err := strconv.Atoi(ssidStr)
+		if err != nil || n < 0 || n > 15 {
+			return nil, fmt.Errorf("SSID must be 0-15: %q", address)
+		}
+		ssid = n
+	}
+
+	addr := make([]byte, 7)
+	for i := 0; i < 6; i++ {
+		c := byte(' ')
+		if i < len(call) {
+			c = call[i]
+			if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+				return nil, fmt.Errorf("invalid character %q in callsign %q", c, address)
+			}
+		}
+		addr[i] = c << 1
+	}
+
+	// SSID byte: flag bit, two reserved bits set, SSID, end-of-address bit
+	addr[6] = flag | 0x60 | byte(ssid)<<1
+	if last {
+		addr[6] |= 0x01
+	}
+	return addr, nil
+}
@@ -0,0 +1,55 @@
+package aprs
+
+import (
+	"packetmap/packet"
+	"strings"
+)
+
+// isNWSAddressee reports whether an addressee is one used by the
+// National Weather Service (and similar) bulletin feeds: "NWS-WARN" or
+// "NWS_WARN", "SKYBOU" or "CWABOU" (SKY/CWA and a three-letter
+// forecast office), or "BOM-..." / "BOM_..." from the Australian feed.
+// Tactical addressees such as "SKYHAWK" don't match.
+func isNWSAddressee(to string) bool {
+	switch {
+	case strings.HasPrefix(to, "NWS-"), strings.HasPrefix(to, "NWS_"),
+		strings.HasPrefix(to, "BOM-"), strings.HasPrefix(to, "BOM_"):
+		return true
+	case len(to) == 6 && (strings.HasPrefix(to, "SKY") || strings.HasPrefix(to, "CWA")):
+		for i := 3; i < len(to); i++ {
+			if to[i] < 'A' || to[i] > 'Z' {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// classifyBulletin decides whether a message addressee makes it a
+// bulletin rather than a direct message. It returns the kind and the
+// bulletin ID, or ok=false for an ordinary message.
+//
+//	BLN0-BLN9        bulletin, ID "0"-"9"
+//	BLNA-BLNZ        announcement, ID "A"-"Z"
+//	BLN3WX           group bulletin "WX", ID "3"
+//	NWS-WARN, SKYBOU weather service bulletin, ID is the addressee
+func classifyBulletin(to string) (kind packet.BulletinKind, id, group string, ok bool) {
+	if strings.HasPrefix(to, "BLN") && len(to) >= 4 {
+		c := to[3]
+		switch {
+		case len(to) == 4 && c >= '0' && c <= '9':
+			return packet.BulletinGeneral, string(c), "", true
+		case len(to) == 4 && c >= 'A' && c <= 'Z':
+			return packet.BulletinAnnouncement, string(c), "", true
+		case c >= '0' && c <= '9':
+			return packet.BulletinGroup, string(c), to[4:], true
+		}
+	}
+
+	if isNWSAddressee(to) {
+		return packet.BulletinNWS, to, "", true
+	}
+
+	return 0, "", "", false
+}
@@ -0,0 +1,99 @@
+package aprs
+
+import (
+	"fmt"
+	"math"
+)
+
+// compressedPosLen is the length of a compressed position block:
+// symbol table (1) + lat (4) + lon (4) + symbol (1) + cs (2) + type (1)
+const compressedPosLen = 13
+
+// isCompressedPosition reports whether body starts with a compressed
+// position. Uncompressed positions always start with a latitude digit,
+// compressed ones start with the symbol table identifier.
+func isCompressedPosition(body string) bool {
+	if len(body) < compressedPosLen {
+		return false
+	}
+	c := body[0]
+	return c == '/' || c == '\\' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'j')
+}
+
+// decodeBase91 decodes a run of Base-91 characters into an integer.
+func decodeBase91(s string) (int, error) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '!' || c > '{' {
+			return 0, fmt.Errorf("invalid base91 character: %q", c)
+		}
+		n = n*91 + int(c-33)
+	}
+	return n, nil
+}
+
+// parseCompressed decodes a Base-91 compressed position report.
+// Format: /YYYYXXXX$csT (symbol table, lat, lon, symbol, course/speed, type)
+// The body must start at the symbol table identifier.
+func parseCompressed(body string) (*position, error) {
+	if len(body) < compressedPosLen {
+		return nil, fmt.Errorf("compressed position too short")
+	}
+
+	latVal, err := decodeBase91(body[1:5])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode latitude: %w", err)
+	}
+	lonVal, err := decodeBase91(body[5:9])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode longitude: %w", err)
+	}
+
+	pos := &position{
+		Lat:         90.0 - float64(latVal)/380926.0,
+		Lon:         -180.0 + float64(lonVal)/190463.0,
+		SymbolTable: body[0],
+		Symbol:      body[9],
+		Compressed:  true,
+		Comment:     body[compressedPosLen:],
+	}
+
+	// Overlay tables a-j stand for digits 0-9
+	if pos.SymbolTable >= 'a' && pos.SymbolTable <= 'j' {
+		pos.SymbolTable = pos.SymbolTable - 'a' + '0'
+	}
+
+	if pos.Lat < -90 || pos.Lat > 90 || pos.Lon < -180 || pos.Lon > 180 {
+		return nil, fmt.Errorf("compressed position out of range")
+	}
+
+	c, s, t := body[10], body[11], body[12]
+
+	// A space in the 'c' byte means no course/speed/range/altitude data
+	if c == ' ' {
+		return pos, nil
+	}
+	if c < '!' || c > '{' || s < '!' || s > '{' || t < '!' || t > '{' {
+		return nil, fmt.Errorf("invalid compressed cs/type bytes")
+	}
+
+	pos.CompressionType = t - 33
+	cv := int(c - 33)
+	sv := int(s - 33)
+
+	switch {
+	case (pos.CompressionType>>3)&0x03 == 0x02:
+		// NMEA source is GGA: cs is altitude
+		pos.Altitude = math.Pow(1.002, float64(cv*91+sv))
+	case cv <= 89:
+		// Course and speed
+		pos.Course = cv * 4
+		pos.Speed = math.Pow(1.08, float64(sv)) - 1
+	case c == '{':
+		// Pre-calculated radio range
+		pos.RadioRange = 2 * math.Pow(1.08, float64(sv))
+	}
+
+	return pos, nil
+}
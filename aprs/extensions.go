@@ -0,0 +1,125 @@
+package aprs
+
+import (
+	"math"
+	"packetmap/packet"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Regexes for the data extensions that may lead a position comment.
+var (
+	courseSpeedRegex = regexp.MustCompile(`^([0-9. ]{3})/([0-9. ]{3})`)
+	phgRegex         = regexp.MustCompile(`^PHG([0-9])([0-9:;<=>?@A-Z])([0-9])([0-8])`)
+	rngRegex         = regexp.MustCompile(`^RNG([0-9]{4})`)
+	dfsRegex         = regexp.MustCompile(`^DFS([0-9])([0-9:;<=>?@A-Z])([0-9])([0-8])`)
+	altitudeRegex    = regexp.MustCompile(`/A=(-?[0-9]{5,6})`)
+	daoRegex         = regexp.MustCompile(`!([\x21-\x7b])([\x20-\x7b])([\x20-\x7b])!`)
+)
+
+// parseDataExtension decodes the optional 7-byte data extension at the
+// start of an uncompressed position comment (CSE/SPD, PHG, RNG or DFS)
+// and strips it from the comment.
+func parseDataExtension(pos *position) {
+	comment := pos.Comment
+
+	if m := courseSpeedRegex.FindStringSubmatch(comment); m != nil {
+		// Unknown course/speed is sent as "..." or "   "
+		if course, err := strconv.Atoi(m[1]); err == nil && course <= 360 {
+			pos.Course = course
+		}
+		if speed, err := strconv.Atoi(m[2]); err == nil {
+			pos.Speed = float64(speed)
+		}
+		pos.Comment = comment[7:]
+		return
+	}
+
+	if m := phgRegex.FindStringSubmatch(comment); m != nil {
+		p, h, g, d := decodePHG(m[1:])
+		pos.PHG = &packet.PHG{Power: p, Height: h, Gain: g, Directivity: d}
+		pos.Comment = comment[7:]
+		return
+	}
+
+	if m := rngRegex.FindStringSubmatch(comment); m != nil {
+		rng, _ := strconv.Atoi(m[1])
+		pos.RadioRange = float64(rng)
+		pos.Comment = comment[7:]
+		return
+	}
+
+	if m := dfsRegex.FindStringSubmatch(comment); m != nil {
+		// The first digit of DFS is signal strength in S-points, not power
+		_, h, g, d := decodePHG(m[1:])
+		pos.DFS = &packet.DFS{Strength: int(m[1][0] - '0'), Height: h, Gain: g, Directivity: d}
+		pos.Comment = comment[7:]
+	}
+}
+
+// decodePHG turns the four PHG/DFS digits into watts, feet, dB and degrees.
+func decodePHG(digits []string) (power, height, gain, directivity int) {
+	p := int(digits[0][0] - '0')
+	h := int(digits[1][0] - '0')
+	power = p * p
+	height = int(10 * math.Pow(2, float64(h)))
+	gain = int(digits[2][0] - '0')
+	directivity = int(digits[3][0]-'0') * 45
+	return
+}
+
+// parseCommentExtensions pulls the altitude and !DAO! extensions out of
+// the free-text comment, which may appear anywhere in it.
+func parseCommentExtensions(pos *position) {
+	if m := altitudeRegex.FindStringSubmatchIndex(pos.Comment); m != nil {
+		if alt, err := strconv.Atoi(pos.Comment[m[2]:m[3]]); err == nil {
+			pos.Altitude = float64(alt)
+		}
+		pos.Comment = pos.Comment[:m[0]] + pos.Comment[m[1]:]
+	}
+
+	if m := daoRegex.FindStringSubmatchIndex(pos.Comment); m != nil {
+		dao := pos.Comment[m[0]+1 : m[1]-1]
+		if applyDAO(pos, dao) {
+			pos.Comment = pos.Comment[:m[0]] + pos.Comment[m[1]:]
+		}
+	}
+
+	pos.Comment = strings.TrimSpace(pos.Comment)
+}
+
+// applyDAO adds the extra precision from a !DAO! extension to the
+// position. Uppercase datum letters carry one extra decimal digit each
+// for lat/lon; lowercase ones carry a Base-91 character each.
+// It returns false if the extension doesn't decode.
+func applyDAO(pos *position, dao string) bool {
+	datum, a, o := dao[0], dao[1], dao[2]
+
+	var latExtra, lonExtra float64 // In minutes
+	switch {
+	case datum >= 'A' && datum <= 'Z':
+		if a == ' ' && o == ' ' {
+			break // Datum only, no extra precision
+		}
+		if a < '0' || a > '9' || o < '0' || o > '9' {
+			return false
+		}
+		latExtra = float64(a-'0') * 0.001
+		lonExtra = float64(o-'0') * 0.001
+	case datum >= 'a' && datum <= 'z':
+		if a < '!' || a > '{' || o < '!' || o > '{' {
+			return false
+		}
+		latExtra = float64(a-33) / 91.0 * 0.01
+		lonExtra = float64(o-33) / 91.0 * 0.01
+	default:
+		return false
+	}
+
+	// The extra digits extend the magnitude, away from the equator/meridian
+	pos.Lat += math.Copysign(latExtra/60.0, pos.Lat)
+	pos.Lon += math.Copysign(lonExtra/60.0, pos.Lon)
+	pos.DAODatum = datum
+	return true
+}
@@ -0,0 +1,64 @@
+package aprs
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ToCall is the destination PacketMap puts on packets it sends. APZ
+// is the experimental software range.
+const ToCall = "APZPKM"
+
+// FormatMessage builds a message payload, ":ADDRESSEE:text{id". The
+// addressee is padded to 9 characters; id may be empty for a message
+// that wants no ack.
+func FormatMessage(to, text, id string) (string, error) {
+	to = strings.ToUpper(to)
+	if len(to) == 0 || len(to) > 9 {
+		return "", fmt.Errorf("addressee must be 1-9 characters: %q", to)
+	}
+	if len(text) > 67 {
+		return "", fmt.Errorf("message text is %d characters, the limit is 67", len(text))
+	}
+	if strings.ContainsAny(text, "|~{") {
+		return "", fmt.Errorf("message text can't contain |, ~ or {")
+	}
+
+	payload := fmt.Sprintf(":%-9s:%s", to, text)
+	if id != "" {
+		payload += "{" + id
+	}
+	return payload, nil
+}
+
+// FormatPosition builds an uncompressed position report without a
+// timestamp for a station that accepts messages:
+// "=DDMM.mmN/DDDMM.mmW>comment"
+func FormatPosition(lat, lon float64, table, symbol byte, comment string) string {
+	latHemi, lonHemi := byte('N'), byte('E')
+	if lat < 0 {
+		latHemi = 'S'
+	}
+	if lon < 0 {
+		lonHemi = 'W'
+	}
+	latDeg, latMin := degreesMinutes(lat)
+	lonDeg, lonMin := degreesMinutes(lon)
+
+	return fmt.Sprintf("=%02d%05.2f%c%c%03d%05.2f%c%c%s",
+		latDeg, latMin, latHemi, table, lonDeg, lonMin, lonHemi, symbol, comment)
+}
+
+// degreesMinutes splits a coordinate into whole degrees and minutes
+// rounded to hundredths, carrying into the degrees at 60
+func degreesMinutes(v float64) (int, float64) {
+	v = math.Abs(v)
+	deg := math.Floor(v)
+	min := math.Round((v-deg)*60*100) / 100
+	if min >= 60 {
+		deg++
+		min -= 60
+	}
+	return int(deg), min
+}
@@ -2,9 +2,55 @@ package aprs
 
 import (
 	"fmt"
+	"packetmap/packet"
 	"strings"
 )
 
+// parseAckReject recognises an "ackID" or "rejID" message body.
+// In the reply-ack scheme the ID may be followed by "}AA", which is dropped.
+func parseAckReject(body string) (packet.MessageKind, string, bool) {
+	var kind packet.MessageKind
+	switch {
+	case strings.HasPrefix(body, "ack"):
+		kind = packet.MessageAck
+	case strings.HasPrefix(body, "rej"):
+		kind = packet.MessageReject
+	default:
+		return packet.MessageText, "", false
+	}
+
+	id := body[3:]
+	if braceIndex := strings.Index(id, "}"); braceIndex != -1 {
+		id = id[:braceIndex]
+	}
+	// Message IDs are 1-5 alphanumerics; anything else is a normal
+	// message that happens to start with "ack"
+	if len(id) < 1 || len(id) > 5 || !isAlphanumeric(id) {
+		return packet.MessageText, "", false
+	}
+	return kind, id, true
+}
+
+// isAlphanumeric reports whether s holds only ASCII letters and digits
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// splitReplyAck splits a "{MM}AA" reply-ack message ID into the
+// message's own ID and the ID of the earlier message it acknowledges.
+func splitReplyAck(id string) (msgID, replyAck string) {
+	if braceIndex := strings.Index(id, "}"); braceIndex != -1 {
+		return id[:braceIndex], id[braceIndex+1:]
+	}
+	return id, ""
+}
+
 // parseMessage parses a message packet (data type ':').
 // Format: :ADDRESSEE :message body{id
 func parseMessage(payload []byte) (to, body, id string, err error) {
@@ -0,0 +1,234 @@
+package aprs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mic-E message codes, indexed by the A/B/C message bits (A is the MSB).
+var miceStandardMessages = [8]string{
+	"Emergency",
+	"Priority",
+	"Special",
+	"Committed",
+	"Returning",
+	"In Service",
+	"En Route",
+	"Off Duty",
+}
+
+var miceCustomMessages = [8]string{
+	"Emergency",
+	"Custom-6",
+	"Custom-5",
+	"Custom-4",
+	"Custom-3",
+	"Custom-2",
+	"Custom-1",
+	"Custom-0",
+}
+
+// miceDevice maps a Mic-E comment prefix/suffix pair to a radio model.
+type miceDevice struct {
+	prefix byte   // Type byte at the start of the comment (0 = any)
+	suffix string // Trailing characters of the comment
+	name   string
+}
+
+// miceDevices is checked in order, so longer/more specific suffixes go first.
+var miceDevices = []miceDevice{
+	{'>', "^", "Kenwood TH-D74"},
+	{'>', "&", "Kenwood TH-D75"},
+	{'>', "=", "Kenwood TH-D72"},
+	{'>', "", "Kenwood TH-D7A"},
+	{']', "=", "Kenwood TM-D710"},
+	{']', "", "Kenwood TM-D700"},
+	{'`', "_ ", "Yaesu VX-8"},
+	{'`', "_\"", "Yaesu FTM-350"},
+	{'`', "_#", "Yaesu VX-8G"},
+	{'`', "_$", "Yaesu FT1D"},
+	{'`', "_%", "Yaesu FTM-400DR"},
+	{'`', "_)", "Yaesu FTM-100D"},
+	{'`', "_(", "Yaesu FT2D"},
+	{'`', "_0", "Yaesu FT3D"},
+	{'`', "_3", "Yaesu FT5D"},
+	{'`', "_1", "Yaesu FTM-300D"},
+	{'`', "_5", "Yaesu FTM-500D"},
+	{'\'', "|3", "Byonics TinyTrack3"},
+	{'\'', "|4", "Byonics TinyTrack4"},
+	{'`', " X", "AP510"},
+	{0, ":4", "SCS GmbH P4dragon DR-7400"},
+	{0, ":8", "SCS GmbH P4dragon DR-7800"},
+}
+
+// miceDestDigit decodes one character of the Mic-E destination address.
+// It returns the latitude digit (' ' for ambiguity), whether the message
+// bit is set, whether the bit is a custom one, and whether the
+// N/S, lon-offset, W/E flag is set for positions 3-5.
+func miceDestDigit(c byte) (digit byte, msgBit, custom, flag bool, err error) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c, false, false, false, nil
+	case c >= 'A' && c <= 'J':
+		return c - 'A' + '0', true, true, false, nil
+	case c == 'K':
+		return ' ', true, true, false, nil
+	case c == 'L':
+		return ' ', false, false, false, nil
+	case c >= 'P' && c <= 'Y':
+		return c - 'P' + '0', true, false, true, nil
+	case c == 'Z':
+		return ' ', true, false, true, nil
+	}
+	return 0, false, false, false, fmt.Errorf("invalid Mic-E destination character: %q", c)
+}
+
+// parseMicE decodes a Mic-E position report (data types '`' and '\”).
+// The latitude, message code and hemisphere flags live in the
+// destination callsign; longitude, speed, course and symbol are in the
+// first 9 bytes of the information field after the data type.
+func parseMicE(dest string, payload []byte) (*position, error) {
+	// Strip any SSID from the destination
+	if dashIndex := strings.Index(dest, "-"); dashIndex != -1 {
+		dest = dest[:dashIndex]
+	}
+	if len(dest) != 6 {
+		return nil, fmt.Errorf("Mic-E destination must be 6 characters: %s", dest)
+	}
+	if len(payload) < 9 {
+		return nil, fmt.Errorf("Mic-E packet too short")
+	}
+	info := payload[1:]
+
+	// 1. Latitude and flags from the destination address
+	latDigits := make([]byte, 6)
+	var msgBits, customCount, stdCount int
+	var south, lonOffset, west bool
+	for i := 0; i < 6; i++ {
+		digit, msgBit, custom, flag, err := miceDestDigit(dest[i])
+		if err != nil {
+			return nil, err
+		}
+		latDigits[i] = digit
+
+		switch i {
+		case 0, 1, 2:
+			msgBits <<= 1
+			if msgBit {
+				msgBits |= 1
+				if custom {
+					customCount++
+				} else {
+					stdCount++
+				}
+			}
+		case 3:
+			south = !flag
+		case 4:
+			lonOffset = flag
+		case 5:
+			west = flag
+		}
+	}
+
+	latDir := "N"
+	if south {
+		latDir = "S"
+	}
+	latStr := string(latDigits)
+	lat, err := parseLat(latStr[0:2], latStr[2:4]+"."+latStr[4:6], latDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Mic-E latitude: %w", err)
+	}
+
+	// 2. Longitude from the information field
+	lonDeg := int(info[0]) - 28
+	if lonOffset {
+		lonDeg += 100
+	}
+	if lonDeg >= 180 && lonDeg <= 189 {
+		lonDeg -= 80
+	} else if lonDeg >= 190 && lonDeg <= 199 {
+		lonDeg -= 190
+	}
+
+	lonMin := int(info[1]) - 28
+	if lonMin >= 60 {
+		lonMin -= 60
+	}
+	lonHun := int(info[2]) - 28
+
+	if lonDeg < 0 || lonDeg > 179 || lonMin < 0 || lonMin > 59 || lonHun < 0 || lonHun > 99 {
+		return nil, fmt.Errorf("invalid Mic-E longitude bytes")
+	}
+
+	lon := float64(lonDeg) + (float64(lonMin)+float64(lonHun)/100.0)/60.0
+	if west {
+		lon = -lon
+	}
+
+	// 3. Speed and course
+	sp := int(info[3]) - 28
+	dc := int(info[4]) - 28
+	se := int(info[5]) - 28
+
+	speed := sp*10 + dc/10
+	if speed >= 800 {
+		speed -= 800
+	}
+	course := (dc%10)*100 + se
+	if course >= 400 {
+		course -= 400
+	}
+
+	pos := &position{
+		Lat:         lat,
+		Lon:         lon,
+		Symbol:      info[6],
+		SymbolTable: info[7],
+		Speed:       float64(speed),
+		Course:      course,
+	}
+
+	// 4. Message code
+	switch {
+	case msgBits == 0:
+		pos.MicEMessage = miceStandardMessages[0]
+	case customCount > 0 && stdCount > 0:
+		pos.MicEMessage = "Unknown"
+	case customCount > 0:
+		pos.MicEMessage = miceCustomMessages[msgBits]
+	default:
+		pos.MicEMessage = miceStandardMessages[msgBits]
+	}
+
+	// 5. Comment: optional device type byte, altitude, then free text
+	comment := string(info[8:])
+	var typeByte byte
+	if len(comment) > 0 && strings.IndexByte(">]`'", comment[0]) != -1 {
+		typeByte = comment[0]
+		comment = comment[1:]
+	}
+
+	if len(comment) >= 4 && comment[3] == '}' {
+		if alt, err := decodeBase91(comment[:3]); err == nil {
+			pos.Altitude = float64(alt-10000) * 3.28084 // meters to feet
+			comment = comment[4:]
+		}
+	}
+
+	for _, d := range miceDevices {
+		if d.prefix != 0 && d.prefix != typeByte {
+			continue
+		}
+		if strings.HasSuffix(comment, d.suffix) {
+			pos.Device = d.name
+			comment = strings.TrimSuffix(comment, d.suffix)
+			break
+		}
+	}
+
+	pos.Comment = comment
+	parseCommentExtensions(pos)
+	return pos, nil
+}
@@ -0,0 +1,231 @@
+package aprs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseNMEA decodes a raw GPS payload (data type '$'). A payload may
+// hold more than one sentence (e.g. GGA followed by VTG); their fields
+// are merged. Supported: RMC, GGA, GLL, VTG and Garmin WPL.
+func parseNMEA(payload []byte) (*position, error) {
+	sentences := strings.Split(strings.TrimSpace(string(payload)), "$")
+
+	pos := &position{
+		// NMEA carries no symbol, use the spec's default dot
+		SymbolTable: '/',
+		Symbol:      '/',
+	}
+	hasPosition := false
+
+	for _, sentence := range sentences {
+		sentence = strings.TrimSpace(sentence)
+		if sentence == "" {
+			continue
+		}
+
+		fields, err := splitNMEA(sentence)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields[0]) < 5 {
+			return nil, fmt.Errorf("invalid NMEA sentence id: %q", fields[0])
+		}
+
+		// Skip the 2-letter talker ID (GP, GN, GL, ...)
+		var ok bool
+		switch fields[0][2:] {
+		case "RMC":
+			ok, err = nmeaRMC(fields, pos)
+		case "GGA":
+			ok, err = nmeaGGA(fields, pos)
+		case "GLL":
+			ok, err = nmeaGLL(fields, pos)
+		case "VTG":
+			err = nmeaVTG(fields, pos)
+		case "WPL":
+			ok, err = nmeaWPL(fields, pos)
+		default:
+			err = fmt.Errorf("unsupported NMEA sentence: %s", fields[0])
+		}
+		if err != nil {
+			return nil, err
+		}
+		hasPosition = hasPosition || ok
+	}
+
+	if !hasPosition {
+		return nil, fmt.Errorf("no valid NMEA position fix")
+	}
+	return pos, nil
+}
+
+// splitNMEA validates the optional *hh checksum and splits the sentence
+// (without the leading '$') into its comma-separated fields.
+func splitNMEA(sentence string) ([]string, error) {
+	if starIndex := strings.LastIndex(sentence, "*"); starIndex != -1 {
+		data, sumStr := sentence[:starIndex], strings.TrimSpace(sentence[starIndex+1:])
+		want, err := strconv.ParseUint(sumStr, 16, 8)
+		if err != nil || len(sumStr) != 2 {
+			return nil, fmt.Errorf("invalid NMEA checksum field: %q", sumStr)
+		}
+		var sum byte
+		for i := 0; i < len(data); i++ {
+			sum ^= data[i]
+		}
+		if sum != byte(want) {
+			return nil, fmt.Errorf("NMEA checksum mismatch: got %02X, want %02X", sum, want)
+		}
+		sentence = data
+	}
+	return strings.Split(sentence, ","), nil
+}
+
+// nmeaLatLon converts NMEA ddmm.mmmm/dddmm.mmmm fields to decimal degrees.
+func nmeaLatLon(latStr, latDir, lonStr, lonDir string) (float64, float64, error) {
+	if len(latStr) < 4 || len(lonStr) < 5 {
+		return 0, 0, fmt.Errorf("NMEA position fields too short")
+	}
+	lat, err := parseLat(latStr[:2], latStr[2:], latDir)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse NMEA latitude: %w", err)
+	}
+	lon, err := parseLon(lonStr[:3], lonStr[3:], lonDir)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse NMEA longitude: %w", err)
+	}
+	return lat, lon, nil
+}
+
+// nmeaTime parses an hhmmss(.ss) time, with an optional ddmmyy date.
+// Without a date, it is the most recent such time.
+func nmeaTime(timeStr, dateStr string, now time.Time) (time.Time, bool) {
+	if len(timeStr) < 6 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("150405", timeStr[:6])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	ref := now.UTC()
+	if len(dateStr) == 6 {
+		d, err := time.Parse("020106", dateStr)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC), true
+	}
+
+	ts := time.Date(ref.Year(), ref.Month(), ref.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	if ts.After(ref.Add(time.Hour)) {
+		ts = ts.AddDate(0, 0, -1)
+	}
+	return ts, true
+}
+
+// nmeaFloat parses an optional numeric field
+func nmeaFloat(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// nmeaRMC: $GPRMC,hhmmss,A,llll.ll,a,yyyyy.yy,a,speed,course,ddmmyy,...
+func nmeaRMC(f []string, pos *position) (bool, error) {
+	if len(f) < 10 {
+		return false, fmt.Errorf("RMC sentence too short")
+	}
+	if f[2] != "A" {
+		return false, nil // Void fix
+	}
+	lat, lon, err := nmeaLatLon(f[3], f[4], f[5], f[6])
+	if err != nil {
+		return false, err
+	}
+	pos.Lat, pos.Lon = lat, lon
+	if v, ok := nmeaFloat(f[7]); ok {
+		pos.Speed = v
+	}
+	if v, ok := nmeaFloat(f[8]); ok {
+		pos.Course = int(v + 0.5)
+	}
+	if ts, ok := nmeaTime(f[1], f[9], time.Now()); ok {
+		pos.Timestamp = ts
+	}
+	return true, nil
+}
+
+// nmeaGGA: $GPGGA,hhmmss,llll.ll,a,yyyyy.yy,a,quality,sats,hdop,alt,M,...
+func nmeaGGA(f []string, pos *position) (bool, error) {
+	if len(f) < 11 {
+		return false, fmt.Errorf("GGA sentence too short")
+	}
+	if f[6] == "" || f[6] == "0" {
+		return false, nil // No fix
+	}
+	lat, lon, err := nmeaLatLon(f[2], f[3], f[4], f[5])
+	if err != nil {
+		return false, err
+	}
+	pos.Lat, pos.Lon = lat, lon
+	if v, ok := nmeaFloat(f[9]); ok && f[10] == "M" {
+		pos.Altitude = v * 3.28084 // meters to feet
+	}
+	if ts, ok := nmeaTime(f[1], "", time.Now()); ok && pos.Timestamp.IsZero() {
+		pos.Timestamp = ts
+	}
+	return true, nil
+}
+
+// nmeaGLL: $GPGLL,llll.ll,a,yyyyy.yy,a,hhmmss,A
+func nmeaGLL(f []string, pos *position) (bool, error) {
+	if len(f) < 5 {
+		return false, fmt.Errorf("GLL sentence too short")
+	}
+	if len(f) > 6 && f[6] != "A" {
+		return false, nil // Data not valid
+	}
+	lat, lon, err := nmeaLatLon(f[1], f[2], f[3], f[4])
+	if err != nil {
+		return false, err
+	}
+	pos.Lat, pos.Lon = lat, lon
+	if len(f) > 5 {
+		if ts, ok := nmeaTime(f[5], "", time.Now()); ok && pos.Timestamp.IsZero() {
+			pos.Timestamp = ts
+		}
+	}
+	return true, nil
+}
+
+// nmeaVTG: $GPVTG,course,T,magcourse,M,knots,N,kmh,K
+// VTG has no position, only course and speed.
+func nmeaVTG(f []string, pos *position) error {
+	if len(f) < 8 {
+		return fmt.Errorf("VTG sentence too short")
+	}
+	if v, ok := nmeaFloat(f[1]); ok {
+		pos.Course = int(v + 0.5)
+	}
+	if v, ok := nmeaFloat(f[5]); ok {
+		pos.Speed = v
+	}
+	return nil
+}
+
+// nmeaWPL: $GPWPL,llll.ll,a,yyyyy.yy,a,name
+// Garmin waypoints are plotted like items, under their own name.
+func nmeaWPL(f []string, pos *position) (bool, error) {
+	if len(f) < 6 {
+		return false, fmt.Errorf("WPL sentence too short")
+	}
+	lat, lon, err := nmeaLatLon(f[1], f[2], f[3], f[4])
+	if err != nil {
+		return false, err
+	}
+	pos.Lat, pos.Lon = lat, lon
+	pos.Name = strings.TrimSpace(f[5])
+	return true, nil
+}
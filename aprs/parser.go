@@ -4,51 +4,36 @@ import (
 	"bytes"
 	"fmt"
 	"packetmap/packet"
-	"strings" // --- ADDED IMPORT ---
 )
 
 // --- NEW HELPER FUNCTION ---
-var telemetryKeywords = []string{
-	"PARM",
-	"UNIT",
-	"EQNS",
-	"BITS",
-	// We can add more known telemetry prefixes here
-}
-
-// isTelemetry checks if a message is likely an automated telemetry report
-// based on keywords or being self-addressed.
+// isTelemetry checks if a message is likely an automated report rather
+// than a user message. Telemetry metadata (PARM/UNIT/EQNS/BITS) and
+// NWS bulletins are decoded before this check, see parseTelemetryMeta
+// and classifyBulletin.
 func isTelemetry(from, to, body string) bool {
 	// Filter 1: Check if it's self-addressed (common for telemetry)
-	if from == to {
-		return true
-	}
-
-	// Filter 2: Check for common telemetry keywords at the start of the body
-	for _, kw := range telemetryKeywords {
-		if strings.HasPrefix(body, kw) {
-			return true
-		}
-	}
-
-	// --- THIS IS THE NEW LINE ---
-	// Filter 3: Check for NWS (National Weather Service) in the sender's callsign
-	if strings.Contains(from, "NWS") {
-		return true
-	}
-	// --- END NEW LINE ---
-
-	return false
+	return from == to
 }
 
 // --- END NEW HELPER FUNCTION ---
 
+// maxThirdPartyDepth limits how many '}' wrappers we unwrap, so a
+// malformed or looping packet can't recurse forever.
+const maxThirdPartyDepth = 3
+
 // Parse takes a raw AX.25 frame (payload from KISS) and returns our
 // internal Packet type if it's a valid position report.
 func Parse(rawFrame []byte) (*packet.Packet, error) {
+	return parse(rawFrame, 0)
+}
+
+// parse does the work for Parse. depth counts the third-party
+// wrappers already removed.
+func parse(rawFrame []byte, depth int) (*packet.Packet, error) {
 
 	// 1. Parse the AX.25 header to get the callsign and APRS payload
-	callsign, payload, err := findPayload(rawFrame)
+	hdr, payload, err := findPayload(rawFrame)
 	if err != nil {
 		return nil, fmt.Errorf("AX.25 parse failed: %w", err)
 	}
@@ -62,39 +47,109 @@ func Parse(rawFrame []byte) (*packet.Packet, error) {
 
 	// --- MODIFIED: Create empty packet first ---
 	pkt := &packet.Packet{
-		Callsign: callsign,
-		Type:     packet.TypeUnknown, // Default to unknown
+		Callsign:    hdr.Source,
+		Destination: hdr.Destination,
+		Path:        hdr.Path,
+		QConstruct:  hdr.QConstruct,
+		IGate:       hdr.IGate,
+		Type:        packet.TypeUnknown, // Default to unknown
 	}
 
 	switch dataType {
 	// --- THIS IS THE FIX ---
 	// Added '=' to the list of uncompressed position types
-	case '!', '/', '=':
-		// Uncompressed position report.
-		lat, lon, err := parseUncompressedPosition(payload)
+	case '!', '/', '=', '@':
+		// Position report, compressed or uncompressed.
+		pos, err := parseUncompressedPosition(payload)
 		if err != nil {
-			return nil, fmt.Errorf("uncompressed position parse failed: %w", err)
+			return nil, fmt.Errorf("position parse failed: %w", err)
 		}
-		// --- MODIFIED: Populate packet ---
-		pkt.Type = packet.TypePosition
-		pkt.Lat = lat
-		pkt.Lon = lon
+		pos.apply(pkt)
+
+	case '`', '\'':
+		// Mic-E position report. Latitude is in the destination address.
+		pos, err := parseMicE(hdr.Destination, payload)
+		if err != nil {
+			return nil, fmt.Errorf("Mic-E parse failed: %w", err)
+		}
+		pos.apply(pkt)
+
+	case '_':
+		// Positionless weather report.
+		wx, timestamp, comment, err := parsePositionlessWeather(payload)
+		if err != nil {
+			return nil, fmt.Errorf("weather parse failed: %w", err)
+		}
+		pkt.Type = packet.TypeWeather
+		pkt.Weather = wx
+		pkt.Timestamp = timestamp
+		pkt.Comment = comment
+
+	case '}':
+		// Third-party packet: the payload is a complete TNC2 packet
+		// injected by a gateway. Parse the inner packet normally.
+		if depth >= maxThirdPartyDepth {
+			return nil, fmt.Errorf("third-party packet nested too deeply")
+		}
+		inner, err := parse(payload[1:], depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("third-party parse failed: %w", err)
+		}
+		// With nested wrappers the innermost one names the original gateway
+		if inner.ThirdPartyGateway == "" {
+			inner.ThirdPartyGateway = hdr.Source
+			inner.ThirdPartyPath = hdr.Path
+		}
+		return inner, nil
+
+	case '>':
+		// Status report.
+		st, err := parseStatus(payload)
+		if err != nil {
+			return nil, fmt.Errorf("status parse failed: %w", err)
+		}
+		pkt.Type = packet.TypeStatus
+		pkt.Status = st.Text
+		pkt.Timestamp = st.Timestamp
+		pkt.StatusLocator = st.Locator
+		if st.Locator != "" {
+			setSymbol(pkt, st.SymbolTable, st.Symbol)
+		}
+		pkt.BeamHeading = st.BeamHeading
+		pkt.ERP = st.ERP
+
+	case 'T':
+		// Telemetry frame.
+		tlm, err := parseTelemetry(payload)
+		if err != nil {
+			return nil, fmt.Errorf("telemetry parse failed: %w", err)
+		}
+		pkt.Type = packet.TypeTelemetry
+		pkt.Telemetry = tlm
+
+	case '$':
+		// Raw NMEA sentence from a GPS.
+		pos, err := parseNMEA(payload)
+		if err != nil {
+			return nil, fmt.Errorf("NMEA parse failed: %w", err)
+		}
+		pos.apply(pkt)
 
 	case ';':
 		// Object position report.
-		lat, lon, err := parseObjectPosition(payload)
+		pos, err := parseObjectPosition(payload)
 		if err != nil {
-			// If it's not a valid object, it might be a malformed packet
-			// with a '!' in it. Fall through to the default logic.
-		} else {
-			// Success! Break out.
-			// --- MODIFIED: Populate packet ---
-			pkt.Type = packet.TypePosition
-			pkt.Lat = lat
-			pkt.Lon = lon
-			break
+			return nil, fmt.Errorf("object parse failed: %w", err)
+		}
+		pos.apply(pkt)
+
+	case ')':
+		// Item report.
+		pos, err := parseItemPosition(payload)
+		if err != nil {
+			return nil, fmt.Errorf("item parse failed: %w", err)
 		}
-		fallthrough // Fall through to default
+		pos.apply(pkt)
 
 	// --- NEW: Handle Message Packets ---
 	case ':':
@@ -103,19 +158,45 @@ func Parse(rawFrame []byte) (*packet.Packet, error) {
 			return nil, fmt.Errorf("message parse failed: %w", err)
 		}
 
+		// Telemetry channel definitions go to the telemetry store
+		if meta := parseTelemetryMeta(to, body); meta != nil {
+			pkt.Type = packet.TypeTelemetry
+			pkt.TelemetryMeta = meta
+			break
+		}
+
+		// Bulletins and announcements go to the bulletin board
+		if kind, bulletinID, group, ok := classifyBulletin(to); ok {
+			pkt.Type = packet.TypeBulletin
+			pkt.MsgTo = to
+			pkt.MsgBody = body
+			pkt.BulletinKind = kind
+			pkt.BulletinID = bulletinID
+			pkt.BulletinGroup = group
+			break
+		}
+
 		// --- NEW FILTERING LOGIC ---
-		if isTelemetry(callsign, to, body) {
+		if isTelemetry(hdr.Source, to, body) {
 			// This is telemetry, not a user message.
 			// We'll return an error, which causes the packet
 			// to be silently ignored by the device loop.
-			return nil, fmt.Errorf("ignoring telemetry/NWS packet: %s", body)
+			return nil, fmt.Errorf("ignoring self-addressed telemetry packet: %s", body)
 		}
 		// --- END NEW FILTERING LOGIC ---
 
 		pkt.Type = packet.TypeMessage
 		pkt.MsgTo = to
+
+		// Acks and rejects carry the ID of the message they answer
+		if kind, ackID, ok := parseAckReject(body); ok && id == "" {
+			pkt.MsgKind = kind
+			pkt.MsgID = ackID
+			break
+		}
+
 		pkt.MsgBody = body
-		pkt.MsgID = id
+		pkt.MsgID, pkt.MsgReplyAck = splitReplyAck(id)
 	// --- END NEW ---
 
 	default:
@@ -125,14 +206,11 @@ func Parse(rawFrame []byte) (*packet.Packet, error) {
 		if idx > 0 && idx < 40 { // aprslib's check
 			// Found one. Treat payload as starting from here.
 			payload = payload[idx:]
-			lat, lon, err := parseUncompressedPosition(payload)
+			pos, err := parseUncompressedPosition(payload)
 			if err != nil {
-				return nil, fmt.Errorf("uncompressed position parse failed: %w", err)
+				return nil, fmt.Errorf("position parse failed: %w", err)
 			}
-			// --- MODIFIED: Populate packet ---
-			pkt.Type = packet.TypePosition
-			pkt.Lat = lat
-			pkt.Lon = lon
+			pos.apply(pkt)
 		} else {
 			return nil, fmt.Errorf("unsupported APRS data type: %c", dataType)
 		}
@@ -144,6 +222,5 @@ func Parse(rawFrame []byte) (*packet.Packet, error) {
 		return nil, fmt.Errorf("packet parsed but type is still unknown")
 	}
 
-
 	return pkt, nil
-}
\ No newline at end of file
+}
@@ -2,9 +2,11 @@ package aprs
 
 import (
 	"fmt"
+	"packetmap/packet"
 	"regexp" // NEW
 	"strconv"
 	"strings" // NEW
+	"time"
 )
 
 // --- NEW: Regex ported from aprslib ---
@@ -76,33 +78,127 @@ func parseLon(degStr, minStr, dirStr string) (float64, error) {
 	return decDeg, nil
 }
 
+// position holds everything decoded from a position report, whether it
+// was sent compressed or uncompressed.
+type position struct {
+	Lat         float64
+	Lon         float64
+	SymbolTable byte
+	Symbol      byte
+	Comment     string
+	Timestamp   time.Time // Zero if the report had no timestamp
+
+	Course          int     // Degrees, 0 if not reported
+	Speed           float64 // Knots
+	Altitude        float64 // Feet
+	RadioRange      float64 // Miles
+	Compressed      bool
+	CompressionType byte // Compressed 'T' byte (minus 33)
+	PHG             *packet.PHG
+	DFS             *packet.DFS
+	DAODatum        byte // Datum letter of a !DAO! extension, 0 if none
+	Weather         *packet.Weather
+
+	MicEMessage string // Mic-E message code, e.g. "En Route"
+	Device      string // Radio model identified from the Mic-E suffix
+
+	Name   string // Object or item name, empty for station positions
+	Killed bool   // Object or item has been killed
+}
+
+// setSymbol stores a symbol table ID and symbol code on pkt. Anything
+// other than the primary/alternate table IDs is an overlay character
+// drawn on top of an alternate-table symbol.
+func setSymbol(pkt *packet.Packet, table, symbol byte) {
+	pkt.Symbol = symbol
+	switch table {
+	case '/', '\\':
+		pkt.SymbolTable = table
+	default:
+		pkt.SymbolTable = '\\'
+		pkt.SymbolOverlay = table
+	}
+}
+
+// apply copies the decoded position into the packet.
+func (p *position) apply(pkt *packet.Packet) {
+	pkt.Type = packet.TypePosition
+	if p.Weather != nil {
+		pkt.Type = packet.TypeWeather
+	}
+	pkt.Weather = p.Weather
+	pkt.Lat = p.Lat
+	pkt.Lon = p.Lon
+	pkt.Timestamp = p.Timestamp
+
+	setSymbol(pkt, p.SymbolTable, p.Symbol)
+	pkt.Course = p.Course
+	pkt.Speed = p.Speed
+	pkt.Altitude = p.Altitude
+	pkt.RadioRange = p.RadioRange
+	pkt.Compressed = p.Compressed
+	pkt.CompressionType = p.CompressionType
+	pkt.PHG = p.PHG
+	pkt.DFS = p.DFS
+	pkt.DAODatum = p.DAODatum
+	pkt.Comment = p.Comment
+	pkt.MicEMessage = p.MicEMessage
+	pkt.Device = p.Device
+
+	// Objects and items are plotted under their own name
+	if p.Name != "" {
+		pkt.Originator = pkt.Callsign
+		pkt.Callsign = p.Name
+		pkt.Killed = p.Killed
+	}
+}
+
 // --- RENAMED & REWRITTEN ---
-// parseNormal handles uncompressed position reports ('!' and '/')
+// parseNormal handles position reports ('!', '/', '=' and '@'), both
+// uncompressed and compressed.
 // It is the Go equivalent of aprslib.parsing.position.parse_normal
-func parseNormal(payload string) (float64, float64, error) {
-	// We expect the payload *with* the '!' or '/' prefix
-	if len(payload) < 18 { // Min length for a valid ! or / packet
-		return 0, 0, fmt.Errorf("packet too short")
+func parseNormal(payload string) (*position, error) {
+	// We expect the payload *with* the data type prefix
+	if len(payload) < 14 { // Min length for a compressed packet
+		return nil, fmt.Errorf("packet too short")
 	}
 
 	// Body starts *after* the data type identifier
 	body := payload[1:]
 	dataType := payload[0]
 
-	// Handle timestamp for '/' packets
-	if dataType == '/' {
+	// Handle timestamp for '/' and '@' packets
+	var timestamp time.Time
+	if dataType == '/' || dataType == '@' {
 		// /HHMMSSz...
 		if len(body) < 7 {
-			return 0, 0, fmt.Errorf("timestamped packet too short")
+			return nil, fmt.Errorf("timestamped packet too short")
+		}
+		// A malformed timestamp shouldn't cost us the position,
+		// so leave it zero rather than failing the whole packet.
+		if ts, err := parseTimestamp(body[:7], time.Now()); err == nil {
+			timestamp = ts
 		}
-		// We don't parse the timestamp yet, just skip it
 		body = body[7:]
 	}
 
+	if isCompressedPosition(body) {
+		pos, err := parseCompressed(body)
+		if err != nil {
+			return nil, err
+		}
+		pos.Timestamp = timestamp
+		if pos.Symbol == '_' {
+			parseCompressedWeather(pos)
+		}
+		parseCommentExtensions(pos)
+		return pos, nil
+	}
+
 	// Use the regex to parse the position
 	matches := normalPosRegex.FindStringSubmatch(body)
 	if matches == nil {
-		return 0, 0, fmt.Errorf("invalid uncompressed position format")
+		return nil, fmt.Errorf("invalid uncompressed position format")
 	}
 
 	// matches[0] is the full string
@@ -118,41 +214,57 @@ func parseNormal(payload string) (float64, float64, error) {
 
 	lat, err := parseLat(matches[1], matches[2], matches[3])
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to parse latitude: %w", err)
+		return nil, fmt.Errorf("failed to parse latitude: %w", err)
 	}
 
 	lon, err := parseLon(matches[5], matches[6], matches[7])
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to parse longitude: %w", err)
+		return nil, fmt.Errorf("failed to parse longitude: %w", err)
+	}
+
+	pos := &position{
+		Lat:         lat,
+		Lon:         lon,
+		SymbolTable: matches[4][0],
+		Symbol:      matches[8][0],
+		Comment:     matches[9],
+		Timestamp:   timestamp,
+	}
+
+	// Weather stations put wind dir/speed where course/speed would go
+	if pos.Symbol != '_' || !parsePositionWeather(pos) {
+		// Course/speed, PHG etc. lead the comment; altitude and DAO may be anywhere
+		parseDataExtension(pos)
 	}
+	parseCommentExtensions(pos)
 
-	return lat, lon, nil
+	return pos, nil
 }
 
 // parseUncompressedPosition is now just a wrapper for parseNormal
-func parseUncompressedPosition(payload []byte) (float64, float64, error) {
+func parseUncompressedPosition(payload []byte) (*position, error) {
 	return parseNormal(string(payload))
 }
 
 // parseObjectPosition handles ';' data type (Object Report)
 // Format: ;OBJECTNAME*HHMMSSzDDMM.hhN/DDDMM.hhW$...
 // --- UPDATED to reuse parseNormal ---
-func parseObjectPosition(payload []byte) (float64, float64, error) {
+func parseObjectPosition(payload []byte) (*position, error) {
 	sPayload := string(payload)
 	dataType := sPayload[0]
 
 	if dataType != ';' {
-		return 0, 0, fmt.Errorf("not an object report")
+		return nil, fmt.Errorf("not an object report")
 	}
 
 	// Min len: ; (1) + OBJNAME(9) + * (1) + TIME(7) + ...
 	if len(sPayload) < 18 {
-		return 0, 0, fmt.Errorf("object packet too short")
+		return nil, fmt.Errorf("object packet too short")
 	}
 
 	// Check for live '*' or dead '_' object marker
 	if sPayload[10] != '*' && sPayload[10] != '_' {
-		return 0, 0, fmt.Errorf("invalid object marker: %c", sPayload[10])
+		return nil, fmt.Errorf("invalid object marker: %c", sPayload[10])
 	}
 
 	// The rest of the packet (from the timestamp on) is a normal position packet
@@ -160,5 +272,49 @@ func parseObjectPosition(payload []byte) (float64, float64, error) {
 	// We make a new string starting with '/' and append the rest.
 	posPayload := "/" + sPayload[11:]
 
-	return parseNormal(posPayload)
-}
\ No newline at end of file
+	pos, err := parseNormal(posPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	pos.Name = strings.TrimSpace(sPayload[1:10])
+	pos.Killed = sPayload[10] == '_'
+	if pos.Name == "" {
+		return nil, fmt.Errorf("object name is blank")
+	}
+	return pos, nil
+}
+
+// parseItemPosition handles ')' data type (Item Report)
+// Format: )NAME!DDMM.hhN/DDDMM.hhW$...
+// The name is 3-9 characters, ended by '!' (live) or '_' (killed).
+// Unlike objects, items carry no timestamp.
+func parseItemPosition(payload []byte) (*position, error) {
+	sPayload := string(payload)
+	if len(sPayload) < 2 || sPayload[0] != ')' {
+		return nil, fmt.Errorf("not an item report")
+	}
+
+	// The name may itself contain '!' or '_' past the 3rd character,
+	// so look for the first marker at or after it.
+	markerIndex := -1
+	for i := 4; i < len(sPayload) && i <= 10; i++ {
+		if sPayload[i] == '!' || sPayload[i] == '_' {
+			markerIndex = i
+			break
+		}
+	}
+	if markerIndex == -1 {
+		return nil, fmt.Errorf("item name marker '!' or '_' not found")
+	}
+
+	// Payload for parseNormal: !DDMM.hhN/DDDMM.hhW$...
+	pos, err := parseNormal("!" + sPayload[markerIndex+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	pos.Name = sPayload[1:markerIndex]
+	pos.Killed = sPayload[markerIndex] == '_'
+	return pos, nil
+}
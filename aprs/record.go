@@ -0,0 +1,49 @@
+package aprs
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"strings"
+	"time"
+)
+
+// NewRecord parses a received frame and wraps it, with its TNC2
+// rendering and any parse error, in a packet.Record.
+func NewRecord(raw []byte, iface string) *packet.Record {
+	rec := &packet.Record{
+		Raw:       raw,
+		TNC2:      FormatTNC2(raw),
+		Received:  time.Now(),
+		Interface: iface,
+	}
+	rec.Packet, rec.Err = Parse(raw)
+	return rec
+}
+
+// FormatTNC2 renders a frame as a TNC2 text line (SRC>DEST,PATH:payload).
+// Text lines are returned as-is; raw AX.25 frames have their address
+// header decoded and their information field shown as sent, so a parse
+// failure can be traced to it. Frames whose header can't be decoded are
+// shown in hex.
+func FormatTNC2(frame []byte) string {
+	if isTNC2(frame) {
+		return strings.TrimRight(string(frame), "\r\n")
+	}
+
+	hdr, payload, err := decodeAX25(frame)
+	if err != nil {
+		return fmt.Sprintf("% X", frame)
+	}
+
+	var b strings.Builder
+	b.WriteString(hdr.Source)
+	b.WriteByte('>')
+	b.WriteString(hdr.Destination)
+	for _, digi := range hdr.Path {
+		b.WriteByte(',')
+		b.WriteString(digi.String())
+	}
+	b.WriteByte(':')
+	b.Write(payload)
+	return b.String()
+}
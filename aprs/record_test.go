@@ -0,0 +1,56 @@
+package aprs
+
+import "testing"
+
+// A raw AX.25 frame whose information field has a '>' and, later, a ':'
+// must be shown and parsed as sent, not cut at the ':' as if it began
+// with a TNC2 header.
+func TestRecordKeepsInfoFieldWithGreaterThanAndColon(t *testing.T) {
+	tests := []struct {
+		name    string
+		dest    string
+		payload string
+	}{
+		{"Mic-E car", "S32U6T", "`c5Ml!2>/`\"4I}146.520MHz 12:30_%"},
+		{"position with time in comment", "APRS", "!4903.50N/07201.75W>Net at 19:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame, err := BuildUIFrame("N0CALL-9", tt.dest, []string{"WIDE1-1"}, []byte(tt.payload))
+			if err != nil {
+				t.Fatalf("BuildUIFrame: %v", err)
+			}
+
+			rec := NewRecord(frame, "test")
+			want := "N0CALL-9>" + tt.dest + ",WIDE1-1:" + tt.payload
+			if rec.TNC2 != want {
+				t.Errorf("TNC2 = %q, want %q", rec.TNC2, want)
+			}
+			if rec.Err != nil {
+				t.Fatalf("parse failed: %v", rec.Err)
+			}
+			if rec.Packet.Lat == 0 || rec.Packet.Lon == 0 {
+				t.Errorf("no position decoded: %+v", rec.Packet)
+			}
+		})
+	}
+}
+
+// A TNC2 header a digipeater left in front of the payload is still
+// stripped for parsing, but the raw log shows the frame as received.
+func TestRecordStripsEmbeddedTNC2Header(t *testing.T) {
+	payload := "N0CALL>APRS,WIDE1-1:!4903.50N/07201.75W-"
+	frame, err := BuildUIFrame("N0CALL", "APRS", nil, []byte(payload))
+	if err != nil {
+		t.Fatalf("BuildUIFrame: %v", err)
+	}
+
+	rec := NewRecord(frame, "test")
+	if want := "N0CALL>APRS:" + payload; rec.TNC2 != want {
+		t.Errorf("TNC2 = %q, want %q", rec.TNC2, want)
+	}
+	if rec.Err != nil {
+		t.Fatalf("parse failed: %v", rec.Err)
+	}
+}
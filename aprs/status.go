@@ -0,0 +1,76 @@
+package aprs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// statusTimestampRegex matches the optional DDHHMMz timestamp that may
+// start a status report. Only zulu DHM is allowed in statuses.
+var statusTimestampRegex = regexp.MustCompile(`^\d{6}z`)
+
+// statusLocatorRegex matches a Maidenhead locator status:
+// 4 or 6 character grid, symbol table, symbol, then optional text.
+var statusLocatorRegex = regexp.MustCompile(`^([A-Ra-r]{2}[0-9]{2}(?:[A-Xa-x]{2})?)([\/\\0-9A-Z])([\x21-\x7e])(?: (.*))?$`)
+
+// beamHeadingRegex matches the ^HP beam heading/power suffix
+var beamHeadingRegex = regexp.MustCompile(`\^([0-9A-Z])([0-9A-Z])$`)
+
+// status holds a decoded status report
+type status struct {
+	Text        string
+	Timestamp   time.Time
+	Locator     string
+	SymbolTable byte // Symbol sent with a locator, 0 otherwise
+	Symbol      byte
+	BeamHeading int // Degrees, -1 if not reported
+	ERP         int // Watts, 0 if not reported
+}
+
+// parseStatus parses a status report (data type '>').
+// Formats:
+//
+//	>status text
+//	>DDHHMMzstatus text
+//	>IO91SX/G status text (Maidenhead locator + symbol)
+//
+// Any of them may end with ^HP, a beam heading and ERP.
+func parseStatus(payload []byte) (*status, error) {
+	body := string(payload[1:])
+	st := &status{BeamHeading: -1}
+
+	if m := statusLocatorRegex.FindStringSubmatch(body); m != nil {
+		// The symbol is kept so the station can be plotted at its locator
+		st.Locator = strings.ToUpper(m[1])
+		st.SymbolTable, st.Symbol = m[2][0], m[3][0]
+		body = m[4]
+	} else if statusTimestampRegex.MatchString(body) {
+		if ts, err := parseTimestamp(body[:7], time.Now()); err == nil {
+			st.Timestamp = ts
+			body = body[7:]
+		}
+	}
+
+	if m := beamHeadingRegex.FindStringSubmatch(body); m != nil {
+		st.BeamHeading = beamValue(m[1][0]) * 10
+		p := beamValue(m[2][0])
+		st.ERP = p * p * 10
+		body = body[:len(body)-3]
+	}
+
+	st.Text = strings.TrimSpace(body)
+	if st.Text == "" && st.Locator == "" {
+		return nil, fmt.Errorf("empty status report")
+	}
+	return st, nil
+}
+
+// beamValue decodes a beam heading/ERP character: 0-9 then A-Z as 10-35.
+func beamValue(c byte) int {
+	if c >= '0' && c <= '9' {
+		return int(c - '0')
+	}
+	return int(c-'A') + 10
+}
@@ -0,0 +1,84 @@
+package aprs
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"strconv"
+	"strings"
+)
+
+// telemetryKeywords are the message prefixes that define how a station's
+// telemetry channels are named, scaled and labelled.
+var telemetryKeywords = []string{
+	"PARM",
+	"UNIT",
+	"EQNS",
+	"BITS",
+}
+
+// parseTelemetry parses a telemetry frame (data type 'T').
+// Format: T#sss,aaa,aaa,aaa,aaa,aaa,bbbbbbbb[comment]
+func parseTelemetry(payload []byte) (*packet.Telemetry, error) {
+	sPayload := string(payload)
+	if !strings.HasPrefix(sPayload, "T#") {
+		return nil, fmt.Errorf("not a telemetry frame")
+	}
+
+	fields := strings.Split(sPayload[2:], ",")
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("telemetry frame has no values")
+	}
+
+	tlm := &packet.Telemetry{Sequence: strings.TrimSpace(fields[0])}
+
+	// Up to 5 analog values, then the 8 digital bits
+	for i, field := range fields[1:] {
+		field = strings.TrimSpace(field)
+		if i == 5 {
+			if len(field) < 8 {
+				return nil, fmt.Errorf("telemetry digital field too short: %q", field)
+			}
+			for bit := 0; bit < 8; bit++ {
+				switch field[bit] {
+				case '1':
+					tlm.Digital[bit] = true
+				case '0':
+				default:
+					return nil, fmt.Errorf("invalid telemetry digital bit: %q", field[bit])
+				}
+			}
+			tlm.HasDigital = true
+			tlm.Comment = strings.TrimSpace(field[8:])
+			break
+		}
+
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid telemetry analog value %q: %w", field, err)
+		}
+		tlm.Analog = append(tlm.Analog, v)
+	}
+
+	return tlm, nil
+}
+
+// parseTelemetryMeta recognises the PARM/UNIT/EQNS/BITS messages a station
+// sends (usually to itself) to describe its telemetry channels.
+// It returns nil if the message body isn't telemetry metadata.
+func parseTelemetryMeta(to, body string) *packet.TelemetryMeta {
+	for _, kw := range telemetryKeywords {
+		if !strings.HasPrefix(body, kw+".") {
+			continue
+		}
+		values := strings.Split(body[len(kw)+1:], ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return &packet.TelemetryMeta{
+			Station: to,
+			Kind:    kw,
+			Values:  values,
+		}
+	}
+	return nil
+}
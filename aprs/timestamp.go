@@ -0,0 +1,108 @@
+package aprs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// parseTimestamp decodes a 7-character APRS timestamp into a time.Time.
+// Supported formats:
+//
+//	DDHHMMz - day/hours/minutes, zulu
+//	DDHHMM/ - day/hours/minutes, local time
+//	HHMMSSh - hours/minutes/seconds, zulu
+//
+// APRS timestamps carry no month or year, so they are resolved against now:
+// a time that would land in the future is taken to be from the previous
+// month (DHM) or day (HMS).
+func parseTimestamp(ts string, now time.Time) (time.Time, error) {
+	if len(ts) != 7 {
+		return time.Time{}, fmt.Errorf("timestamp must be 7 characters: %q", ts)
+	}
+
+	a, errA := strconv.Atoi(ts[0:2])
+	b, errB := strconv.Atoi(ts[2:4])
+	c, errC := strconv.Atoi(ts[4:6])
+	if errA != nil || errB != nil || errC != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp digits: %q", ts)
+	}
+
+	switch ts[6] {
+	case 'z', '/':
+		day, hour, minute := a, b, c
+		if day < 1 || day > 31 || hour > 23 || minute > 59 {
+			return time.Time{}, fmt.Errorf("timestamp out of range: %q", ts)
+		}
+		loc := time.UTC
+		if ts[6] == '/' {
+			loc = time.Local
+		}
+		ref := now.In(loc)
+		t, ok := dateIn(ref.Year(), ref.Month(), day, hour, minute, 0, loc)
+		// Allow a little clock skew before assuming last month. A day
+		// this month doesn't have (the 31st in a 30-day month) can only
+		// be from last month too.
+		if !ok || t.After(ref.Add(time.Hour)) {
+			t, ok = dateIn(ref.Year(), ref.Month()-1, day, hour, minute, 0, loc)
+			if !ok {
+				return time.Time{}, fmt.Errorf("timestamp day not in this or last month: %q", ts)
+			}
+		}
+		return t, nil
+
+	case 'h':
+		hour, minute, second := a, b, c
+		if hour > 23 || minute > 59 || second > 59 {
+			return time.Time{}, fmt.Errorf("timestamp out of range: %q", ts)
+		}
+		ref := now.UTC()
+		t := time.Date(ref.Year(), ref.Month(), ref.Day(), hour, minute, second, 0, time.UTC)
+		if t.After(ref.Add(time.Hour)) {
+			t = t.AddDate(0, 0, -1)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unknown timestamp format: %c", ts[6])
+}
+
+// parseMDHMTimestamp decodes the 8-digit MMDDHHMM (zulu) timestamp used
+// by positionless weather reports. Like parseTimestamp, a time in the
+// future is assumed to be from last year.
+func parseMDHMTimestamp(ts string, now time.Time) (time.Time, error) {
+	if len(ts) != 8 {
+		return time.Time{}, fmt.Errorf("MDHM timestamp must be 8 characters: %q", ts)
+	}
+
+	var fields [4]int
+	for i := range fields {
+		v, err := strconv.Atoi(ts[i*2 : i*2+2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp digits: %q", ts)
+		}
+		fields[i] = v
+	}
+	month, day, hour, minute := fields[0], fields[1], fields[2], fields[3]
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("timestamp out of range: %q", ts)
+	}
+
+	ref := now.UTC()
+	t, ok := dateIn(ref.Year(), time.Month(month), day, hour, minute, 0, time.UTC)
+	if !ok || t.After(ref.Add(time.Hour)) {
+		t, ok = dateIn(ref.Year()-1, time.Month(month), day, hour, minute, 0, time.UTC)
+		if !ok {
+			return time.Time{}, fmt.Errorf("timestamp date does not exist: %q", ts)
+		}
+	}
+	return t, nil
+}
+
+// dateIn builds a time in the given month, reporting false if the month
+// has no such day. Month 0 is December of the year before, as with
+// time.Date.
+func dateIn(year int, month time.Month, day, hour, minute, second int, loc *time.Location) (time.Time, bool) {
+	t := time.Date(year, month, day, hour, minute, second, 0, loc)
+	return t, t.Day() == day
+}
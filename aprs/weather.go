@@ -0,0 +1,176 @@
+package aprs
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weatherFields maps each weather element key to its value width, e.g.
+// "t072" is a 3-digit temperature. 's' shows up twice: as wind speed in
+// positionless reports and as snowfall after the rain fields.
+var weatherFields = map[byte]int{
+	'c': 3, // Wind direction (degrees)
+	's': 3, // Wind speed (mph) or snowfall (inches)
+	'g': 3, // Gust (mph)
+	't': 3, // Temperature (F)
+	'r': 3, // Rain last hour (1/100 inch)
+	'p': 3, // Rain last 24 hours (1/100 inch)
+	'P': 3, // Rain since midnight (1/100 inch)
+	'h': 2, // Humidity (%)
+	'b': 5, // Barometric pressure (1/10 mbar)
+	'L': 3, // Luminosity (W/m^2, below 1000)
+	'l': 3, // Luminosity (W/m^2, 1000 and above)
+	'#': 3, // Raw rain counter
+}
+
+// parseWeather decodes a run of weather elements (c...s...g...t...).
+// When windPrefix is true the data starts with the "ddd/sss" wind
+// direction/speed used in position+weather reports.
+// It returns the weather and whatever text follows the weather data
+// (usually a software/station type), or an error if nothing decoded.
+func parseWeather(data string, windPrefix bool) (*packet.Weather, string, error) {
+	wx := &packet.Weather{}
+	found := false
+
+	if windPrefix {
+		if len(data) < 7 || data[3] != '/' {
+			return nil, data, fmt.Errorf("missing weather wind direction/speed")
+		}
+		if v, ok := weatherValue(data[0:3]); ok {
+			wx.WindDirection = &v
+		}
+		if v, ok := weatherValue(data[4:7]); ok {
+			wx.WindSpeed = &v
+		}
+		data = data[7:]
+		found = true
+	}
+
+	for len(data) > 0 {
+		key := data[0]
+		width, ok := weatherFields[key]
+		if !ok || len(data) < 1+width {
+			break
+		}
+		raw := data[1 : 1+width]
+		v, ok := weatherValue(raw)
+		if !ok && strings.Trim(raw, ". ") != "" {
+			break // Not weather data; the rest is comment
+		}
+		data = data[1+width:]
+		found = true
+		if !ok {
+			continue // Field present but value unknown ("...")
+		}
+
+		switch key {
+		case 'c':
+			wx.WindDirection = &v
+		case 's':
+			if wx.WindSpeed == nil && wx.Temperature == nil {
+				wx.WindSpeed = &v
+			} else {
+				wx.Snow = &v
+			}
+		case 'g':
+			wx.WindGust = &v
+		case 't':
+			wx.Temperature = &v
+		case 'r':
+			v /= 100
+			wx.Rain1h = &v
+		case 'p':
+			v /= 100
+			wx.Rain24h = &v
+		case 'P':
+			v /= 100
+			wx.RainMidnight = &v
+		case 'h':
+			// h00 means 100%
+			if v == 0 {
+				v = 100
+			}
+			wx.Humidity = &v
+		case 'b':
+			v /= 10
+			wx.Pressure = &v
+		case 'L':
+			wx.Luminosity = &v
+		case 'l':
+			v += 1000
+			wx.Luminosity = &v
+		}
+	}
+
+	if !found {
+		return nil, data, fmt.Errorf("no weather data found")
+	}
+	return wx, strings.TrimSpace(data), nil
+}
+
+// weatherValue parses a weather element, reporting false for the
+// "..." / "   " placeholders stations send for missing sensors.
+func weatherValue(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// parsePositionlessWeather handles the '_' data type.
+// Format: _MMDDHHMMc...s...g...t...
+func parsePositionlessWeather(payload []byte) (*packet.Weather, time.Time, string, error) {
+	sPayload := string(payload)
+	if len(sPayload) < 9 {
+		return nil, time.Time{}, "", fmt.Errorf("weather report too short")
+	}
+
+	timestamp, err := parseMDHMTimestamp(sPayload[1:9], time.Now())
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+
+	wx, rest, err := parseWeather(sPayload[9:], false)
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+	return wx, timestamp, rest, nil
+}
+
+// parsePositionWeather decodes the weather data carried in the comment
+// of an uncompressed position report with the weather symbol. It
+// returns false, leaving pos untouched, if the comment isn't weather.
+func parsePositionWeather(pos *position) bool {
+	wx, rest, err := parseWeather(pos.Comment, true)
+	if err != nil {
+		return false
+	}
+	pos.Weather = wx
+	pos.Comment = rest
+	return true
+}
+
+// parseCompressedWeather decodes weather from a compressed position
+// report, where wind direction/speed ride in the cs bytes.
+func parseCompressedWeather(pos *position) {
+	wx, rest, err := parseWeather(pos.Comment, false)
+	if err != nil {
+		wx = &packet.Weather{}
+	}
+	if pos.Course != 0 || pos.Speed != 0 {
+		dir := float64(pos.Course)
+		speed := pos.Speed * 1.15078 // knots to mph
+		wx.WindDirection = &dir
+		wx.WindSpeed = &speed
+		pos.Course, pos.Speed = 0, 0
+	}
+	if err != nil && wx.WindSpeed == nil {
+		return // No weather after all
+	}
+	pos.Weather = wx
+	pos.Comment = rest
+}
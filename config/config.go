@@ -2,13 +2,23 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
 
+// SymbolStyle sets how an APRS symbol is drawn on the map
+type SymbolStyle struct {
+	Glyph string `toml:"glyph"` // Single character drawn at the station position
+	Color string `toml:"color"` // lipgloss colour, e.g. "11" or "#ff8800"
+}
+
 // MapConfig holds map-specific settings
 type MapConfig struct {
 	DefaultZoom float64 `toml:"defaultzoom"`
+	// Symbols overrides the default glyphs, keyed by table + symbol code
+	// (e.g. "/>" for a car, "\\#" for a digipeater on the alternate table)
+	Symbols map[string]SymbolStyle `toml:"symbols"`
 }
 
 // StationConfig holds settings specific to the user's station
@@ -16,13 +26,76 @@ type StationConfig struct {
 	Callsign   string `toml:"callsign"`
 	GridSquare string `toml:"gridsquare"`
 	// Passcode removed from here
+
+	// Our own position beacon, sent from the gridsquare centre
+	Symbol  string `toml:"symbol"`  // Table and symbol, default "/-" (house)
+	Comment string `toml:"comment"` // Beacon comment
+	Beacon  int    `toml:"beacon"`  // Minutes between beacons (at least 10), 0 for manual only
+}
+
+// KISSConfig holds KISS TNC parameters sent when the interface connects.
+// Parameters left unset keep the TNC's own settings.
+type KISSConfig struct {
+	Port        int    `toml:"port"`        // TNC port (0-15) to transmit on and to send these parameters to
+	TXDelay     *int   `toml:"txdelay"`     // Keyup delay in ms (sent in 10 ms units)
+	Persistence *int   `toml:"persistence"` // p-persistence, 0-255
+	SlotTime    *int   `toml:"slottime"`    // Slot interval in ms (sent in 10 ms units)
+	TXTail      *int   `toml:"txtail"`      // Time to hold the transmitter after a frame, in ms
+	FullDuplex  *bool  `toml:"fullduplex"`
+	SetHardware string `toml:"sethardware"` // TNC-specific SetHardware payload as hex, e.g. "0a01"
+}
+
+// SerialConfig holds serial port settings for KISS TNCs
+type SerialConfig struct {
+	Baud        int      `toml:"baud"`        // Default 9600
+	DataBits    int      `toml:"databits"`    // 5-8, default 8
+	Parity      string   `toml:"parity"`      // none, odd, even, mark or space (default none)
+	StopBits    float64  `toml:"stopbits"`    // 1, 1.5 or 2 (default 1)
+	FlowControl string   `toml:"flowcontrol"` // none or rtscts (default none)
+	Init        []string `toml:"init"`        // Commands sent before KISS starts, e.g. ["KISS ON", "RESTART"]
+}
+
+// AGWConfig holds settings for AGWPE interfaces
+type AGWConfig struct {
+	Port int `toml:"port"` // Radio port to transmit on, counting from 0
+}
+
+// APRSISConfig holds settings for APRS-IS interfaces
+type APRSISConfig struct {
+	// Servers are tried in order until one accepts us; "host" or
+	// "host:port". Default rotate.aprs.net.
+	Servers []string `toml:"servers"`
+	Port    int      `toml:"port"` // For servers given without a port, default 14580
+	// Filter is sent as-is at login, e.g. "r/41.5/-81/100 b/N0CALL*".
+	// When empty, a range filter around the station gridsquare is used.
+	Filter        string `toml:"filter"`
+	Radius        int    `toml:"radius"` // Range filter radius in km, default 2000
+	TLS           bool   `toml:"tls"`
+	TLSSkipVerify bool   `toml:"tlsskipverify"` // Accept any certificate, for testing against a local server
+	// Follow starts with the filter following the map: an a/ area
+	// filter for the view is sent whenever it pans or zooms
+	Follow bool `toml:"follow"`
+	// StallTimeout is how many seconds without hearing anything from
+	// the server, keepalives included, before reconnecting (default 90)
+	StallTimeout int `toml:"stalltimeout"`
 }
 
 // InterfaceConfig holds settings for the TNC/network connection
 type InterfaceConfig struct {
-	Type     string `toml:"type"`
-	Device   string `toml:"device"`
-	Passcode int    `toml:"passcode"`
+	Name     string       `toml:"name"` // Shown in the UI and raw log; defaults to the type and device
+	Type     string       `toml:"type"`
+	Device   string       `toml:"device"`
+	Passcode int          `toml:"passcode"`
+	KISS     KISSConfig   `toml:"kiss"`
+	Serial   SerialConfig `toml:"serial"`
+	AGW      AGWConfig    `toml:"agw"`
+	APRSIS   APRSISConfig `toml:"aprsis"`
+	// Transport is "tcp" or "serial" for KISS; when empty, a device
+	// containing ':' is taken to be ip:port and anything else a serial port
+	Transport string `toml:"transport"`
+	// MaxRetries is how many failed reconnects in a row to allow
+	// before giving up on the interface (0 keeps trying forever)
+	MaxRetries int `toml:"maxretries"`
 }
 
 // --- NEW ---
@@ -33,12 +106,51 @@ type MsgbarConfig struct {
 
 // --- END NEW ---
 
+// BulletinConfig holds settings for the bulletin board
+type BulletinConfig struct {
+	Expiry int `toml:"expiry"` // Minutes before an unrefreshed bulletin is dropped (default 240)
+}
+
+// DisplayName is the interface's configured name, or one made up from
+// its type and device
+func (i InterfaceConfig) DisplayName() string {
+	if i.Name != "" {
+		return i.Name
+	}
+	switch strings.ToUpper(i.Type) {
+	case "APRSIS":
+		return "APRS-IS"
+	}
+	return strings.ToUpper(i.Type) + " " + i.Device
+}
+
 // Config holds all application configuration
 type Config struct {
+	// Dedupe is how many seconds the same packet heard again on another
+	// interface is dropped for. 0 uses the default of 30, -1 turns
+	// deduplication off.
+	Dedupe int `toml:"dedupe"`
+
 	Station   StationConfig   `toml:"station"`
 	Map       MapConfig       `toml:"map"`
 	Interface InterfaceConfig `toml:"interface"`
-	Msgbar    MsgbarConfig    `toml:"msgbar"` // --- ADDED ---
+	// Interfaces lists several interfaces to run at once ([[interfaces]]
+	// tables). When set, Interface is ignored.
+	Interfaces []InterfaceConfig `toml:"interfaces"`
+	Msgbar     MsgbarConfig      `toml:"msgbar"` // --- ADDED ---
+	Bulletin   BulletinConfig    `toml:"bulletin"`
+}
+
+// AllInterfaces returns the interfaces to run: the [[interfaces]] list
+// if there is one, otherwise the single [interface]
+func (c Config) AllInterfaces() []InterfaceConfig {
+	if len(c.Interfaces) > 0 {
+		return c.Interfaces
+	}
+	if c.Interface.Type == "" {
+		return nil
+	}
+	return []InterfaceConfig{c.Interface}
 }
 
 // LoadConfig reads the configuration from the specified path
@@ -0,0 +1,238 @@
+// Package agw is a client for the AGWPE TCP API offered by soundcard
+// modems such as Direwolf, SoundModem and AGWPE itself.
+package agw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"packetmap/aprs"
+	"packetmap/config"
+	"packetmap/packet"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultAddress is where AGWPE listens unless told otherwise
+const defaultAddress = "127.0.0.1:8000"
+
+// headerLen is the size of the fixed header before every frame's data
+const headerLen = 36
+
+// maxDataLen guards against a corrupt length field
+const maxDataLen = 64 * 1024
+
+// AGWPE data kinds used here
+const (
+	kindVersion  byte = 'R' // Version request / reply
+	kindPortInfo byte = 'G' // Port information request / reply
+	kindRegister byte = 'X' // Register a callsign / result
+	kindMonitor  byte = 'k' // Toggle raw frame monitoring
+	kindRaw      byte = 'K' // Raw AX.25 frame, received or to transmit
+)
+
+// frame is one AGWPE API frame
+type frame struct {
+	Port     byte
+	Kind     byte
+	PID      byte
+	CallFrom string
+	CallTo   string
+	Data     []byte
+}
+
+// Client represents an active connection to an AGWPE server
+type Client struct {
+	conn net.Conn
+	name string // Interface name used to tag received records
+	port int    // Radio port we transmit on
+
+	sendMu sync.Mutex // Serialises writes
+}
+
+// Connect dials the AGWPE server, checks it answers, registers our
+// callsign and turns on raw monitoring. conf supplies the station
+// details, iface the interface's own settings.
+func Connect(conf config.Config, iface config.InterfaceConfig) (*Client, error) {
+	address := iface.Device
+	if address == "" {
+		address = defaultAddress
+	}
+	if iface.AGW.Port < 0 || iface.AGW.Port > 255 {
+		return nil, fmt.Errorf("AGW port must be 0-255, got %d", iface.AGW.Port)
+	}
+
+	log.Printf("Attempting AGWPE connection to: %s", address)
+	conn, err := net.DialTimeout("tcp", address, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to AGWPE server at %s: %w", address, err)
+	}
+	c := &Client{conn: conn, name: "AGW " + address, port: iface.AGW.Port}
+
+	if err := c.handshake(); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	// Ask for the port list, register our callsign (some servers
+	// won't transmit for us otherwise) and turn on raw monitoring.
+	// Their replies are logged by Start.
+	requests := []frame{{Kind: kindPortInfo}}
+	if call := strings.ToUpper(conf.Station.Callsign); call != "" {
+		requests = append(requests, frame{Kind: kindRegister, CallFrom: call})
+	}
+	requests = append(requests, frame{Kind: kindMonitor})
+	for _, req := range requests {
+		if err := c.writeFrame(req); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to send AGWPE '%c' request: %w", req.Kind, err)
+		}
+	}
+
+	log.Println("Successfully connected to AGWPE server")
+	return c, nil
+}
+
+// handshake asks for the server version, so a port that isn't AGWPE
+// fails now rather than silently never sending anything
+func (c *Client) handshake() error {
+	if err := c.writeFrame(frame{Kind: kindVersion}); err != nil {
+		return fmt.Errorf("failed to send AGWPE version request: %w", err)
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	defer c.conn.SetReadDeadline(time.Time{})
+
+	for {
+		f, err := readFrame(c.conn)
+		if err != nil {
+			return fmt.Errorf("no AGWPE version reply: %w", err)
+		}
+		if f.Kind == kindVersion && len(f.Data) >= 8 {
+			major := binary.LittleEndian.Uint16(f.Data[0:2])
+			minor := binary.LittleEndian.Uint16(f.Data[4:6])
+			log.Printf("AGWPE server version %d.%d", major, minor)
+			return nil
+		}
+	}
+}
+
+// Start begins the frame-reading loop.
+// Raw 'K' frames are parsed as APRS and sent down the provided channel
+// as records tagged with the radio port; replies to our requests are
+// logged. This function should be run as a goroutine.
+func (c *Client) Start(packetChan chan<- *packet.Record) {
+	for {
+		f, err := readFrame(c.conn)
+		if err != nil {
+			// If the connection is closed, err will be io.EOF or similar
+			close(packetChan) // Signal to the app that we're done
+			return
+		}
+
+		switch f.Kind {
+		case kindRaw:
+			// The data starts with a KISS-style type byte, then the AX.25 frame
+			if len(f.Data) < 2 {
+				continue
+			}
+			rec := aprs.NewRecord(f.Data[1:], c.name)
+			rec.Port = int(f.Port)
+			packetChan <- rec
+
+		case kindPortInfo:
+			// "2;Port1 description;Port2 description;"
+			log.Printf("AGWPE ports: %s", strings.TrimRight(string(f.Data), "\x00"))
+
+		case kindRegister:
+			if len(f.Data) > 0 && f.Data[0] == 1 {
+				log.Printf("AGWPE registered callsign %s", f.CallFrom)
+			} else {
+				log.Printf("AGWPE refused to register callsign %s", f.CallFrom)
+			}
+		}
+	}
+}
+
+// Send builds an AX.25 UI frame and transmits it as a raw 'K' frame on
+// the configured port. path is the digipeater path, e.g.
+// []string{"WIDE1-1", "WIDE2-1"}. It is safe to call from any goroutine.
+func (c *Client) Send(source, destination string, path []string, payload string) error {
+	ax25, err := aprs.BuildUIFrame(source, destination, path, []byte(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build AX.25 frame: %w", err)
+	}
+
+	f := frame{
+		Port:     byte(c.port),
+		Kind:     kindRaw,
+		CallFrom: source,
+		CallTo:   destination,
+		Data:     append([]byte{0x00}, ax25...),
+	}
+	if err := c.writeFrame(f); err != nil {
+		return fmt.Errorf("failed to write AGWPE frame: %w", err)
+	}
+	return nil
+}
+
+// writeFrame encodes and sends one frame
+func (c *Client) writeFrame(f frame) error {
+	buf := make([]byte, headerLen+len(f.Data))
+	buf[0] = f.Port
+	buf[4] = f.Kind
+	buf[6] = f.PID
+	copy(buf[8:18], f.CallFrom)
+	copy(buf[18:28], f.CallTo)
+	binary.LittleEndian.PutUint32(buf[28:32], uint32(len(f.Data)))
+	copy(buf[headerLen:], f.Data)
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// readFrame reads one frame: the 36-byte header, then its data
+func readFrame(r io.Reader) (frame, error) {
+	var hdr [headerLen]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return frame{}, err
+	}
+
+	dataLen := binary.LittleEndian.Uint32(hdr[28:32])
+	if dataLen > maxDataLen {
+		return frame{}, fmt.Errorf("AGWPE frame too long: %d bytes", dataLen)
+	}
+	data := make([]byte, dataLen)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return frame{}, err
+	}
+
+	return frame{
+		Port:     hdr[0],
+		Kind:     hdr[4],
+		PID:      hdr[6],
+		CallFrom: callString(hdr[8:18]),
+		CallTo:   callString(hdr[18:28]),
+		Data:     data,
+	}, nil
+}
+
+// callString trims a NUL-padded callsign field
+func callString(b []byte) string {
+	if i := strings.IndexByte(string(b), 0); i != -1 {
+		b = b[:i]
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// Close disconnects the client
+func (c *Client) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
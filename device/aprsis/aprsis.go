@@ -2,6 +2,8 @@ package aprsis
 
 import (
 	"bufio"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,15 +12,18 @@ import (
 	"packetmap/config"
 	"packetmap/packet"
 	mapview "packetmap/ui/map" // --- RE-ADDED: Import mapview for GridSquareToLatLon ---
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // APRS-IS server details
 const (
-	aprsisServer = "rotate.aprs.net:14580" // Using unfiltered port, but filter *command* might still work
-	appName      = "PacketMap"
-	appVersion   = "0.1"
+	defaultServer   = "rotate.aprs.net" // Rotates between the core servers
+	defaultPort     = 14580             // User-defined filter port
+	appName         = "PacketMap"
+	appVersion      = "0.1"
 	defaultRadiusKm = 2000 // Default filter radius
 )
 
@@ -29,15 +34,23 @@ type Client struct {
 	callsign   string
 	filter     string // --- RE-ADDED ---
 	IsVerified bool
+
+	writeMu sync.Mutex // Serialises writes after login
+
+	stallTimeout time.Duration // Reconnect after this long without hearing anything
+	healthMu     sync.Mutex
+	health       Health
+	recent       []time.Time // Packet line times in the last minute
 }
 
-// Connect establishes a connection to an APRS-IS server
-func Connect(conf config.Config) (*Client, error) {
+// Connect establishes a connection to an APRS-IS server. conf supplies
+// the station details, iface the interface's own settings.
+func Connect(conf config.Config, iface config.InterfaceConfig) (*Client, error) {
 	callsign := conf.Station.Callsign
 	if callsign == "" {
 		return nil, fmt.Errorf("callsign missing in config for APRS-IS")
 	}
-	passcode := conf.Interface.Passcode
+	passcode := iface.Passcode
 	if passcode <= 0 {
 		log.Printf("Warning: APRS-IS passcode not provided or invalid in config, connecting read-only.")
 		passcode = -1
@@ -52,50 +65,121 @@ func Connect(conf config.Config) (*Client, error) {
 		}
 	}
 
+	filterStr := iface.APRSIS.Filter
+	if filterStr == "" {
+		filterStr = rangeFilter(conf.Station.GridSquare, iface.APRSIS.Radius)
+	}
+
+	// Try each server in turn until one lets us log in
+	var failures []string
+	for _, addr := range servers(iface) {
+		conn, err := dial(addr, iface.APRSIS)
+		if err != nil {
+			log.Printf("APRS-IS server %s failed: %v", addr, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", addr, err))
+			continue
+		}
+
+		client := &Client{
+			conn:         conn,
+			reader:       bufio.NewReader(conn),
+			callsign:     callsign,
+			filter:       filterStr, // --- RE-ADDED ---
+			stallTimeout: time.Duration(iface.APRSIS.StallTimeout) * time.Second,
+		}
+		if client.stallTimeout <= 0 {
+			client.stallTimeout = defaultStallTimeout
+		}
+
+		// Perform login
+		if err := client.login(passcode); err != nil {
+			client.Close()
+			log.Printf("APRS-IS login to %s failed: %v", addr, err)
+			failures = append(failures, fmt.Sprintf("%s: login failed: %v", addr, err))
+			continue
+		}
+
+		log.Println("APRS-IS Login successful")
+		return client, nil
+	}
+	return nil, fmt.Errorf("failed to connect to any APRS-IS server: %s", strings.Join(failures, "; "))
+}
+
+// rangeFilter builds an r/ filter around the station gridsquare
+func rangeFilter(stationGrid string, radiusKm int) string {
+	if radiusKm <= 0 {
+		radiusKm = defaultRadiusKm
+	}
+
 	// --- RE-ADDED: Calculate filter based on gridsquare ---
 	var filterStr string
-	stationGrid := conf.Station.GridSquare
 	if stationGrid != "" {
 		lon, lat, err := mapview.GridSquareToLatLon(stationGrid)
 		if err != nil {
 			log.Printf("Warning: Could not parse station gridsquare '%s' for APRS-IS filter: %v. Using default filter.", stationGrid, err)
 			// Fallback to a wide default if gridsquare is invalid
-			filterStr = fmt.Sprintf("r/%.3f/%.3f/%d", 41.5, -81.0, defaultRadiusKm*2) // Centered roughly on Ohio
+			filterStr = fmt.Sprintf("r/%.3f/%.3f/%d", 41.5, -81.0, radiusKm*2) // Centered roughly on Ohio
 		} else {
 			log.Printf("Setting APRS-IS filter based on gridsquare %s (Lat: %.3f, Lon: %.3f)", stationGrid, lat, lon)
-			// TODO: Make radius configurable?
-			filterStr = fmt.Sprintf("r/%.3f/%.3f/%d", lat, lon, defaultRadiusKm)
+			filterStr = fmt.Sprintf("r/%.3f/%.3f/%d", lat, lon, radiusKm)
 		}
 	} else {
 		log.Printf("Warning: Station gridsquare not found in config. Using default APRS-IS filter.")
 		// Fallback to a wide default if no gridsquare is set
-		filterStr = fmt.Sprintf("r/%.3f/%.3f/%d", 41.5, -81.0, defaultRadiusKm*2) // Centered roughly on Ohio
+		filterStr = fmt.Sprintf("r/%.3f/%.3f/%d", 41.5, -81.0, radiusKm*2) // Centered roughly on Ohio
 	}
 	// --- End Re-added Filter Logic ---
+	return filterStr
+}
 
-	log.Printf("Attempting APRS-IS connection to %s", aprsisServer) // Log still mentions server:port
-	conn, err := net.DialTimeout("tcp", aprsisServer, 15*time.Second)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to APRS-IS server %s: %w", aprsisServer, err)
+// servers returns the addresses to try, in order. A device set on the
+// interface is used when no server list is given.
+func servers(iface config.InterfaceConfig) []string {
+	port := iface.APRSIS.Port
+	if port <= 0 {
+		port = defaultPort
+	}
+
+	list := iface.APRSIS.Servers
+	if len(list) == 0 && iface.Device != "" {
+		list = []string{iface.Device}
+	}
+	if len(list) == 0 {
+		list = []string{defaultServer}
 	}
-	log.Printf("Connected to APRS-IS server: %s", conn.RemoteAddr())
 
-	client := &Client{
-		conn:     conn,
-		reader:   bufio.NewReader(conn),
-		callsign: callsign,
-		filter:   filterStr, // --- RE-ADDED ---
+	addrs := make([]string, 0, len(list))
+	for _, server := range list {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(strings.Trim(server, "[]"), strconv.Itoa(port))
+		}
+		addrs = append(addrs, server)
 	}
+	return addrs
+}
+
+// dial connects to one server, over TLS if configured
+func dial(addr string, conf config.APRSISConfig) (net.Conn, error) {
+	log.Printf("Attempting APRS-IS connection to %s (TLS: %v)", addr, conf.TLS)
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
 
-	// Perform login
-	err = client.login(passcode)
+	var conn net.Conn
+	var err error
+	if conf.TLS {
+		host, _, _ := net.SplitHostPort(addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: conf.TLSSkipVerify,
+		})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
 	if err != nil {
-		client.Close()
-		return nil, fmt.Errorf("APRS-IS login failed: %w", err)
+		return nil, err
 	}
 
-	log.Println("APRS-IS Login successful")
-	return client, nil
+	log.Printf("Connected to APRS-IS server: %s", conn.RemoteAddr())
+	return conn, nil
 }
 
 // login sends the login string and verifies the response
@@ -131,6 +215,9 @@ func (c *Client) login(passcode int) error {
 		}
 		line := strings.TrimSpace(string(lineBytes))
 		log.Printf("APRS-IS Server: %s", line)
+		if strings.HasPrefix(line, "#") {
+			c.noteComment(line, time.Now())
+		}
 
 		if strings.HasPrefix(line, "# logresp ") {
 			// # logresp <callsign> verified|invalid ..., server <serverid>
@@ -139,7 +226,8 @@ func (c *Client) login(passcode int) error {
 				if parts[2] == c.callsign {
 					if strings.HasPrefix(parts[3], "verified") {
 						c.IsVerified = (passcode != -1) // Verified only if we sent a real passcode
-						return nil                      // Success!
+						c.setVerified(c.IsVerified)
+						return nil // Success!
 					} else {
 						// Even if login is "invalid" (e.g. bad passcode), server might keep connection open read-only.
 						// Treat this as success for read-only purposes.
@@ -165,15 +253,21 @@ func (c *Client) login(passcode int) error {
 }
 
 // Start begins the packet-reading loop for APRS-IS.
-func (c *Client) Start(packetChan chan<- *packet.Packet) {
+// Servers send a '#' keepalive every 20 seconds or so; if nothing at
+// all arrives within the stall timeout the link is taken to be dead,
+// and the channel is closed so the connection can be redialled.
+func (c *Client) Start(packetChan chan<- *packet.Record) {
 	log.Println("Starting APRS-IS packet reader...")
 
 	for {
-		c.conn.SetReadDeadline(time.Time{}) // Ensure no lingering deadline
+		c.conn.SetReadDeadline(time.Now().Add(c.stallTimeout))
 
 		lineBytes, err := c.reader.ReadBytes('\n')
 		if err != nil {
-			if err != io.EOF {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				log.Printf("Nothing from APRS-IS server for %s, dropping the connection", c.stallTimeout)
+				c.conn.Close()
+			} else if err != io.EOF {
 				log.Printf("Error reading APRS-IS stream: %v", err)
 			} else {
 				log.Println("APRS-IS connection closed.")
@@ -183,21 +277,78 @@ func (c *Client) Start(packetChan chan<- *packet.Packet) {
 		}
 
 		line := strings.TrimSpace(string(lineBytes))
-
-		// Ignore comments and empty lines
-		if len(line) == 0 || line[0] == '#' {
+		if len(line) == 0 {
 			continue
 		}
 
-		// Try to parse the line as an APRS packet
-		pkt, err := aprs.Parse([]byte(line))
-		if err != nil {
-			// log.Printf("Failed to parse APRS-IS line: %v -- Line: %s", err, line) // Keep commented out
+		// Comments are keepalives and server notices
+		if line[0] == '#' {
+			c.noteComment(line, time.Now())
 			continue
 		}
 
-		packetChan <- pkt
+		// Parse the line and hand the record, good or bad, to the main app
+		c.notePacket(time.Now())
+		packetChan <- aprs.NewRecord([]byte(line), "APRS-IS")
+	}
+}
+
+// ErrNotVerified is returned by Send when the server hasn't verified
+// our passcode, so anything we sent would be dropped
+var ErrNotVerified = errors.New("APRS-IS login is not verified, refusing to transmit")
+
+// Send transmits a packet as a TNC2 line, SOURCE>DESTINATION,TCPIP*:payload.
+// RF paths mean nothing on APRS-IS, so path is ignored and the packet
+// always carries TCPIP*. It refuses unless our login was verified.
+// It is safe to call from any goroutine.
+func (c *Client) Send(source, destination string, path []string, payload string) error {
+	if !c.IsVerified {
+		return ErrNotVerified
+	}
+	if source == "" || destination == "" {
+		return fmt.Errorf("source and destination are required")
+	}
+	if strings.ContainsAny(source+destination+payload, "\r\n") {
+		return fmt.Errorf("packet can't contain line breaks")
+	}
+
+	line := fmt.Sprintf("%s>%s,TCPIP*:%s", strings.ToUpper(source), strings.ToUpper(destination), payload)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	log.Printf("Sending to APRS-IS: %s", line)
+	if _, err := c.conn.Write([]byte(line + "\r\n")); err != nil {
+		return fmt.Errorf("failed to send to APRS-IS: %w", err)
+	}
+	return nil
+}
+
+// setVerified records the login result for Health
+func (c *Client) setVerified(verified bool) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.health.Verified = verified
+}
+
+// Filter returns the server-side filter in use
+func (c *Client) Filter() string {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.filter
+}
+
+// SetFilter replaces the server-side filter on a live connection
+// with a #filter command. It is safe to call from any goroutine.
+func (c *Client) SetFilter(filter string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	log.Printf("Sending APRS-IS filter: %s", filter)
+	if _, err := c.conn.Write([]byte("#filter " + filter + "\r\n")); err != nil {
+		return err
 	}
+	c.filter = filter
+	return nil
 }
 
 // Close disconnects the client
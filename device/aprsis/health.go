@@ -0,0 +1,87 @@
+package aprsis
+
+import (
+	"strings"
+	"time"
+)
+
+// defaultStallTimeout is how long we wait without hearing anything,
+// keepalives included, before giving up on the connection. Servers
+// send a keepalive every 20 seconds or so.
+const defaultStallTimeout = 90 * time.Second
+
+// Health describes how an APRS-IS connection is doing
+type Health struct {
+	Server        string    // Server name from logresp, e.g. "T2TEXAS"
+	Software      string    // Server software from the banner, e.g. "aprsc 2.1.14"
+	Verified      bool      // Logged in with a valid passcode
+	LastData      time.Time // Last packet line
+	LastKeepalive time.Time // Last '#' line from the server
+	PacketsPerMin int       // Packet lines in the last minute
+}
+
+// Health returns the connection's current health
+func (c *Client) Health() Health {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+
+	c.pruneRecent(time.Now())
+	h := c.health
+	h.PacketsPerMin = len(c.recent)
+	return h
+}
+
+// notePacket records a packet line for the rate and last-data time
+func (c *Client) notePacket(now time.Time) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+
+	c.health.LastData = now
+	c.recent = append(c.recent, now)
+	c.pruneRecent(now)
+}
+
+// pruneRecent drops packet times older than a minute
+func (c *Client) pruneRecent(now time.Time) {
+	i := 0
+	for i < len(c.recent) && now.Sub(c.recent[i]) > time.Minute {
+		i++
+	}
+	c.recent = c.recent[i:]
+}
+
+// noteComment records a '#' line: a keepalive or banner, which names
+// the server software, or the logresp, which names the server
+func (c *Client) noteComment(line string, now time.Time) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+
+	c.health.LastKeepalive = now
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#"))
+	if len(fields) == 0 {
+		return
+	}
+	switch fields[0] {
+	case "logresp":
+		// # logresp <callsign> verified, server <serverid>
+		for i, f := range fields {
+			if strings.TrimSuffix(f, ",") == "server" && i+1 < len(fields) {
+				c.health.Server = fields[i+1]
+			}
+		}
+	case "filter", "Port":
+		// Replies to our commands and port notices, not the banner
+	default:
+		// # aprsc 2.1.14-g5e22b37 16 Oct 2026 12:00:00 GMT T2TEXAS 1.2.3.4:14580
+		software := fields[0]
+		if len(fields) > 1 {
+			software += " " + strings.SplitN(fields[1], "-", 2)[0]
+		}
+		c.health.Software = software
+		// aprsc names itself after the date in keepalives
+		if len(fields) >= 8 && fields[6] == "GMT" {
+			c.health.Server = fields[7]
+		}
+	}
+}
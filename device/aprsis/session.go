@@ -0,0 +1,119 @@
+package aprsis
+
+import (
+	"fmt"
+	"log"
+	"packetmap/config"
+	"sync"
+)
+
+// Session is one APRS-IS interface across reconnects. It remembers a
+// filter changed at runtime, so a new connection logs in with it
+// rather than the one from config.toml.
+type Session struct {
+	conf  config.Config
+	iface config.InterfaceConfig
+
+	mu     sync.Mutex
+	filter string  // Runtime filter, empty to use the configured one
+	client *Client // Latest connection, may have dropped
+}
+
+// NewSession creates a session; nothing is dialled until Connect
+func NewSession(conf config.Config, iface config.InterfaceConfig) *Session {
+	return &Session{conf: conf, iface: iface}
+}
+
+// Connect opens a new connection, using the runtime filter if one has
+// been set
+func (s *Session) Connect() (*Client, error) {
+	s.mu.Lock()
+	iface := s.iface
+	if s.filter != "" {
+		iface.APRSIS.Filter = s.filter
+	}
+	s.mu.Unlock()
+
+	client, err := Connect(s.conf, iface)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.client = client
+	changed := s.filter != "" && s.filter != iface.APRSIS.Filter
+	filter := s.filter
+	s.mu.Unlock()
+
+	// The filter changed while we were logging in
+	if changed {
+		if err := client.SetFilter(filter); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to send filter: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// Send transmits through the current connection; see Client.Send
+func (s *Session) Send(source, destination string, path []string, payload string) error {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return fmt.Errorf("not connected to APRS-IS")
+	}
+	return client.Send(source, destination, path, payload)
+}
+
+// Name is the interface name the session's records are tagged with
+func (s *Session) Name() string {
+	return s.iface.DisplayName()
+}
+
+// Health returns the health of the latest connection, or false if
+// there hasn't been one yet
+func (s *Session) Health() (Health, bool) {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return Health{}, false
+	}
+	return client.Health(), true
+}
+
+// Filter returns the filter in use
+func (s *Session) Filter() string {
+	s.mu.Lock()
+	filter, client := s.filter, s.client
+	s.mu.Unlock()
+
+	switch {
+	case filter != "":
+		return filter
+	case client != nil:
+		return client.Filter()
+	}
+	return s.iface.APRSIS.Filter
+}
+
+// SetFilter changes the server-side filter. It is sent straight away
+// if we're connected, and used for every later login.
+func (s *Session) SetFilter(filter string) error {
+	s.mu.Lock()
+	s.filter = filter
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return nil // Not connected yet; Connect will use it
+	}
+	if err := client.SetFilter(filter); err != nil {
+		log.Printf("APRS-IS filter saved for the next connection: %v", err)
+		return fmt.Errorf("failed to send filter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package kiss
+
+import (
+	"encoding/hex"
+	"fmt"
+	"packetmap/config"
+)
+
+// KISS command codes, the low nibble of a frame's type byte.
+// The high nibble is the TNC port.
+const (
+	CmdData        byte = 0x00 // AX.25 frame to transmit or that was received
+	CmdTXDelay     byte = 0x01 // Keyup delay, in 10 ms units
+	CmdPersistence byte = 0x02 // p-persistence parameter, 0-255
+	CmdSlotTime    byte = 0x03 // Slot interval, in 10 ms units
+	CmdTXTail      byte = 0x04 // Transmitter hold time after a frame, in 10 ms units
+	CmdFullDuplex  byte = 0x05 // 0 for half duplex, anything else for full duplex
+	CmdSetHardware byte = 0x06 // TNC-specific
+	CmdReturn      byte = 0xFF // Leave KISS mode (sent without a port)
+)
+
+// maxPort is the highest port a KISS type byte can address
+const maxPort = 15
+
+// splitType splits a KISS type byte into its port and command
+func splitType(b byte) (port int, cmd byte) {
+	return int(b >> 4), b & 0x0F
+}
+
+// SendCommand sends a KISS command frame to the given TNC port.
+// It is safe to call from any goroutine.
+func (c *Client) SendCommand(port int, cmd byte, data []byte) error {
+	if port < 0 || port > maxPort {
+		return fmt.Errorf("KISS port must be 0-%d, got %d", maxPort, port)
+	}
+
+	typeByte := byte(port)<<4 | cmd&0x0F
+	if cmd == CmdReturn {
+		typeByte = CmdReturn
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if c.encoder == nil {
+		c.encoder = NewEncoder(c.conn)
+	}
+	return c.encoder.WriteFrame(append([]byte{typeByte}, data...))
+}
+
+// configure sends the parameters set in conf to the TNC
+func (c *Client) configure(conf config.KISSConfig) error {
+	if conf.Port < 0 || conf.Port > maxPort {
+		return fmt.Errorf("KISS port must be 0-%d, got %d", maxPort, conf.Port)
+	}
+	c.port = conf.Port
+
+	// Times are configured in ms but sent in 10 ms units
+	params := []struct {
+		name  string
+		cmd   byte
+		value *int
+		scale int
+	}{
+		{"txdelay", CmdTXDelay, conf.TXDelay, 10},
+		{"persistence", CmdPersistence, conf.Persistence, 1},
+		{"slottime", CmdSlotTime, conf.SlotTime, 10},
+		{"txtail", CmdTXTail, conf.TXTail, 10},
+	}
+	for _, p := range params {
+		if p.value == nil {
+			continue
+		}
+		v := *p.value / p.scale
+		if *p.value < 0 || v > 255 {
+			return fmt.Errorf("KISS %s out of range: %d", p.name, *p.value)
+		}
+		if err := c.SendCommand(c.port, p.cmd, []byte{byte(v)}); err != nil {
+			return fmt.Errorf("failed to send KISS %s: %w", p.name, err)
+		}
+	}
+
+	if conf.FullDuplex != nil {
+		var v byte
+		if *conf.FullDuplex {
+			v = 1
+		}
+		if err := c.SendCommand(c.port, CmdFullDuplex, []byte{v}); err != nil {
+			return fmt.Errorf("failed to send KISS fullduplex: %w", err)
+		}
+	}
+
+	if conf.SetHardware != "" {
+		data, err := hex.DecodeString(conf.SetHardware)
+		if err != nil {
+			return fmt.Errorf("invalid KISS sethardware hex %q: %w", conf.SetHardware, err)
+		}
+		if err := c.SendCommand(c.port, CmdSetHardware, data); err != nil {
+			return fmt.Errorf("failed to send KISS sethardware: %w", err)
+		}
+	}
+	return nil
+}
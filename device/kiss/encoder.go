@@ -0,0 +1,39 @@
+package kiss
+
+import (
+	"bufio"
+	"io"
+)
+
+// Encoder writes KISS frames to an io.Writer
+type Encoder struct {
+	w *bufio.Writer
+}
+
+// NewEncoder creates a new KISS frame encoder
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// WriteFrame writes a single KISS frame, wrapped in FENDs.
+// The frame starts with the type byte (0x00 for data on port 0), the
+// same way ReadFrame returns it. FEND and FESC bytes are escaped.
+func (e *Encoder) WriteFrame(frame []byte) error {
+	e.w.WriteByte(FEND)
+	for _, b := range frame {
+		switch b {
+		case FEND:
+			e.w.WriteByte(FESC)
+			e.w.WriteByte(TFEND)
+		case FESC:
+			e.w.WriteByte(FESC)
+			e.w.WriteByte(TFESC)
+		default:
+			e.w.WriteByte(b)
+		}
+	}
+	e.w.WriteByte(FEND)
+
+	// bufio.Writer keeps the first error, so checking Flush is enough
+	return e.w.Flush()
+}
@@ -8,11 +8,17 @@ import (
 	"packetmap/config"
 	"packetmap/packet"
 	"strings"
+	"sync"
 )
 
 // Client represents an active connection to a KISS TNC
 type Client struct {
 	conn io.ReadWriteCloser // The underlying connection (TCP, Serial, etc.)
+	name string             // Interface name used to tag received records
+	port int                // TNC port we transmit on
+
+	sendMu  sync.Mutex // Serialises writes from Send and SendCommand
+	encoder *Encoder
 }
 
 // Connect establishes a connection to a TNC based on the interface config
@@ -21,26 +27,27 @@ func Connect(conf config.InterfaceConfig) (*Client, error) {
 
 	switch strings.ToUpper(conf.Type) {
 	case "KISS":
-		// Check if device is TCP or Serial
-		if strings.Contains(conf.Device, ":") {
-			// This is the existing TCP logic
+		switch transport(conf) {
+		case "tcp":
 			log.Printf("Attempting KISS TCP connection to: %s", conf.Device)
 			tcpConn, err := connectTCP(conf.Device)
 			if err != nil {
 				return nil, err
 			}
 			log.Println("Successfully connected to KISS TNC via TCP")
-			return &Client{conn: tcpConn}, nil
+			return newClient(tcpConn, conf)
 
-		} else {
-			// This is the new Serial logic
+		case "serial":
 			log.Printf("Attempting KISS Serial connection to: %s", conf.Device)
-			serialConn, err := connectSerial(conf.Device)
+			serialConn, err := connectSerial(conf.Device, conf.Serial)
 			if err != nil {
 				return nil, err
 			}
 			log.Println("Successfully connected to KISS TNC via Serial")
-			return &Client{conn: serialConn}, nil
+			return newClient(serialConn, conf)
+
+		default:
+			return nil, fmt.Errorf("unknown KISS transport: %s (use tcp or serial)", conf.Transport)
 		}
 
 	case "APRSIS":
@@ -51,11 +58,35 @@ func Connect(conf config.InterfaceConfig) (*Client, error) {
 	}
 }
 
+// transport returns the configured transport, or guesses it from the
+// device: ip:port is TCP, anything else a serial port
+func transport(conf config.InterfaceConfig) string {
+	if conf.Transport != "" {
+		return strings.ToLower(conf.Transport)
+	}
+	if strings.Contains(conf.Device, ":") {
+		return "tcp"
+	}
+	return "serial"
+}
+
+// newClient wraps an open connection and sends the configured KISS
+// parameters to the TNC
+func newClient(conn io.ReadWriteCloser, conf config.InterfaceConfig) (*Client, error) {
+	c := &Client{conn: conn, name: "KISS " + conf.Device}
+	if err := c.configure(conf.KISS); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
 // Start begins the packet-reading loop.
 // It uses the Decoder to read frames and the aprs.Parse to parse them.
-// Valid packets are sent down the provided channel.
+// Every frame is sent down the provided channel as a record, with the
+// parse error attached if it didn't decode.
 // This function should be run as a goroutine.
-func (c *Client) Start(packetChan chan<- *packet.Packet) {
+func (c *Client) Start(packetChan chan<- *packet.Record) {
 	decoder := NewDecoder(c.conn)
 	// log.Println("Starting KISS packet reader...") // REMOVED
 
@@ -69,8 +100,13 @@ func (c *Client) Start(packetChan chan<- *packet.Packet) {
 			return
 		}
 
-		// A valid KISS data frame has 0x00 as the first byte (port 0)
-		if len(frame) < 1 || frame[0] != 0x00 {
+		// The type byte holds the port in the high nibble and the
+		// command in the low one; only data frames carry packets
+		if len(frame) < 1 {
+			continue
+		}
+		port, cmd := splitType(frame[0])
+		if cmd != CmdData {
 			// log.Printf("Got non-data KISS frame (cmd %X), ignoring", frame[0])
 			continue
 		}
@@ -78,21 +114,26 @@ func (c *Client) Start(packetChan chan<- *packet.Packet) {
 		// The rest of the frame is the AX.25 packet
 		ax25Frame := frame[1:]
 
-		// Try to parse it as APRS
-		pkt, err := aprs.Parse(ax25Frame)
-		if err != nil {
-			// log.Printf("Failed to parse APRS: %v", err) // REMOVED
-			// log.Printf("--- Failing Packet Start ---") // REMOVED
-			// log.Printf("Payload (string): %s", string(ax25Frame)) // REMOVED
-			// log.Printf("Payload (hex):    %x", ax25Frame) // REMOVED
-			// log.Printf("--- Failing Packet End ---") // REMOVED
-			continue
-		}
+		// Parse it as APRS and hand the record, good or bad, to the main app
+		rec := aprs.NewRecord(ax25Frame, c.name)
+		rec.Port = port
+		packetChan <- rec
+	}
+}
+
+// Send builds an AX.25 UI frame and transmits it through the TNC on
+// the configured port. path is the digipeater path, e.g. []string{"WIDE1-1", "WIDE2-1"}.
+// It is safe to call from any goroutine.
+func (c *Client) Send(source, destination string, path []string, payload string) error {
+	frame, err := aprs.BuildUIFrame(source, destination, path, []byte(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build AX.25 frame: %w", err)
+	}
 
-		// Success! Send the packet to the main app
-		// log.Printf("Got Packet: %s (%.3f, %.3f)", pkt.Callsign, pkt.Lat, pkt.Lon) // REMOVED
-		packetChan <- pkt
+	if err := c.SendCommand(c.port, CmdData, frame); err != nil {
+		return fmt.Errorf("failed to write KISS frame: %w", err)
 	}
+	return nil
 }
 
 // Close disconnects the client
@@ -0,0 +1,162 @@
+package kiss
+
+import (
+	"bytes"
+	"fmt"
+	"packetmap/config"
+	"strings"
+	"time"
+
+	"go.bug.st/serial"
+	"go.bug.st/serial/enumerator"
+)
+
+// defaultInitCommands put common TNC-2 style TNCs into KISS mode
+var defaultInitCommands = []string{"KISS ON", "RESTART"}
+
+// ProbeResult describes what was found on one serial port
+type ProbeResult struct {
+	Port        string // Device path, e.g. /dev/ttyUSB0 or COM3
+	Description string // USB product and IDs, if known
+	KISS        bool   // The port looks like a KISS TNC
+	Verdict     string // What we found, for the user
+}
+
+// Probe tries each serial port in turn to find a KISS TNC. A TNC that
+// answers a carriage return with its command prompt is sent the init
+// commands (conf.Init, or "KISS ON" and "RESTART"); then the port is
+// watched for listen for KISS frames. A KISS TNC says nothing until it
+// hears a packet, so a silent port may still be one.
+func Probe(conf config.SerialConfig, listen time.Duration) ([]ProbeResult, error) {
+	mode, err := serialMode(conf)
+	if err != nil {
+		return nil, err
+	}
+	commands := conf.Init
+	if len(commands) == 0 {
+		commands = defaultInitCommands
+	}
+
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serial ports: %w", err)
+	}
+
+	results := make([]ProbeResult, 0, len(ports))
+	for _, p := range ports {
+		result := probePort(p.Name, mode, commands, listen)
+		if p.IsUSB {
+			result.Description = fmt.Sprintf("%s (USB %s:%s)", p.Product, p.VID, p.PID)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// probePort checks one serial port for a TNC
+func probePort(name string, mode *serial.Mode, commands []string, listen time.Duration) ProbeResult {
+	result := ProbeResult{Port: name}
+
+	port, err := serial.Open(name, mode)
+	if err != nil {
+		result.Verdict = fmt.Sprintf("could not open: %v", err)
+		return result
+	}
+	defer port.Close()
+
+	if err := port.SetReadTimeout(100 * time.Millisecond); err != nil {
+		result.Verdict = fmt.Sprintf("could not set read timeout: %v", err)
+		return result
+	}
+	port.ResetInputBuffer()
+
+	// A TNC in command mode answers a bare carriage return with its
+	// prompt (e.g. "cmd:"); one in KISS mode ignores bytes outside a frame
+	var notes []string
+	if _, err := port.Write([]byte("\r")); err != nil {
+		result.Verdict = fmt.Sprintf("write failed: %v", err)
+		return result
+	}
+	if reply := readFor(port, time.Second); isText(reply) {
+		notes = append(notes, fmt.Sprintf("command prompt %q answered", sample(reply)))
+		if err := sendInit(port, commands); err != nil {
+			result.Verdict = err.Error()
+			return result
+		}
+		notes = append(notes, "sent "+strings.Join(commands, ", "))
+		readFor(port, time.Second) // Discard the TNC's replies
+		result.KISS = true
+	}
+
+	data := readFor(port, listen)
+	frames := countFrames(data)
+	switch {
+	case frames > 0:
+		notes = append(notes, fmt.Sprintf("%d KISS frames heard", frames))
+		result.KISS = true
+	case len(data) > 0:
+		notes = append(notes, fmt.Sprintf("unrecognised data %q", sample(data)))
+		result.KISS = false
+	case result.KISS:
+		notes = append(notes, "now in KISS mode, no packets heard yet")
+	default:
+		notes = append(notes, "silent: a KISS TNC with nothing heard yet, or no TNC")
+	}
+
+	result.Verdict = strings.Join(notes, "; ")
+	return result
+}
+
+// readFor collects whatever the port sends within d
+func readFor(port serial.Port, d time.Duration) []byte {
+	var data []byte
+	buf := make([]byte, 256)
+	for deadline := time.Now().Add(d); time.Now().Before(deadline); {
+		n, err := port.Read(buf)
+		if err != nil {
+			break
+		}
+		data = append(data, buf[:n]...)
+	}
+	return data
+}
+
+// countFrames counts the KISS data frames in data that are long enough
+// to hold an AX.25 header
+func countFrames(data []byte) int {
+	decoder := NewDecoder(bytes.NewReader(data))
+	count := 0
+	for {
+		frame, err := decoder.ReadFrame()
+		if err != nil {
+			return count
+		}
+		if _, cmd := splitType(frame[0]); cmd == CmdData && len(frame) > 16 {
+			count++
+		}
+	}
+}
+
+// isText reports whether data is mostly printable text, as a TNC's
+// command interpreter would send
+func isText(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	printable := 0
+	for _, b := range data {
+		if b >= ' ' && b <= '~' || b == '\r' || b == '\n' {
+			printable++
+		}
+	}
+	return printable*10 >= len(data)*9
+}
+
+// sample returns the start of data, trimmed, for messages
+func sample(data []byte) string {
+	s := strings.TrimSpace(string(data))
+	if len(s) > 40 {
+		s = s[:40] + "..."
+	}
+	return s
+}
@@ -3,21 +3,33 @@ package kiss
 import (
 	"fmt"
 	"io"
+	"packetmap/config"
+	"strings"
 	"time"
 
 	"go.bug.st/serial"
 )
 
+// defaultBaud is a common rate for TNCs
+const defaultBaud = 9600
+
+// ctsTimeout is how long a write waits for the TNC to raise CTS
+// when RTS/CTS flow control is on
+const ctsTimeout = 5 * time.Second
+
 // connectSerial opens a connection to a serial KISS TNC
-func connectSerial(devicePath string) (io.ReadWriteCloser, error) {
+func connectSerial(devicePath string, conf config.SerialConfig) (io.ReadWriteCloser, error) {
 	if devicePath == "" {
 		return nil, fmt.Errorf("no device path (e.g., /dev/ttyUSB0 or COM3) provided for KISS serial")
 	}
 
-	// TODO: The baud rate should be configurable in config.toml
-	// For now, we default to 9600, a common rate for TNCs.
-	mode := &serial.Mode{
-		BaudRate: 9600,
+	mode, err := serialMode(conf)
+	if err != nil {
+		return nil, err
+	}
+	rtscts, err := flowControl(conf.FlowControl)
+	if err != nil {
+		return nil, err
 	}
 
 	port, err := serial.Open(devicePath, mode)
@@ -32,5 +44,120 @@ func connectSerial(devicePath string) (io.ReadWriteCloser, error) {
 		return nil, fmt.Errorf("failed to set read timeout: %w", err)
 	}
 
-	return port, nil
-}
\ No newline at end of file
+	conn := serialConn{Port: port, rtscts: rtscts}
+	if err := sendInit(conn, conf.Init); err != nil {
+		port.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// serialMode builds the port settings, filling in the defaults
+func serialMode(conf config.SerialConfig) (*serial.Mode, error) {
+	mode := &serial.Mode{
+		BaudRate: conf.Baud,
+		DataBits: conf.DataBits,
+	}
+	if mode.BaudRate == 0 {
+		mode.BaudRate = defaultBaud
+	}
+	if mode.DataBits == 0 {
+		mode.DataBits = 8
+	}
+	if mode.DataBits < 5 || mode.DataBits > 8 {
+		return nil, fmt.Errorf("serial data bits must be 5-8, got %d", mode.DataBits)
+	}
+
+	switch strings.ToLower(conf.Parity) {
+	case "", "none", "n":
+		mode.Parity = serial.NoParity
+	case "odd", "o":
+		mode.Parity = serial.OddParity
+	case "even", "e":
+		mode.Parity = serial.EvenParity
+	case "mark", "m":
+		mode.Parity = serial.MarkParity
+	case "space", "s":
+		mode.Parity = serial.SpaceParity
+	default:
+		return nil, fmt.Errorf("unknown serial parity: %q", conf.Parity)
+	}
+
+	switch conf.StopBits {
+	case 0, 1:
+		mode.StopBits = serial.OneStopBit
+	case 1.5:
+		mode.StopBits = serial.OnePointFiveStopBits
+	case 2:
+		mode.StopBits = serial.TwoStopBits
+	default:
+		return nil, fmt.Errorf("serial stop bits must be 1, 1.5 or 2, got %v", conf.StopBits)
+	}
+
+	return mode, nil
+}
+
+// flowControl reports whether RTS/CTS handshaking is wanted
+func flowControl(setting string) (bool, error) {
+	switch strings.ToLower(setting) {
+	case "", "none":
+		return false, nil
+	case "rtscts", "hardware":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown serial flow control: %q (use none or rtscts)", setting)
+}
+
+// sendInit sends text commands, each ended with a carriage return, to
+// a TNC still in its command interpreter (e.g. "KISS ON", "RESTART")
+func sendInit(w io.Writer, commands []string) error {
+	for _, cmd := range commands {
+		if _, err := w.Write([]byte(cmd + "\r")); err != nil {
+			return fmt.Errorf("failed to send TNC init command %q: %w", cmd, err)
+		}
+		// Give the TNC time to act on it, RESTART especially
+		time.Sleep(500 * time.Millisecond)
+	}
+	return nil
+}
+
+// serialConn hides read timeouts from the KISS decoder. serial.Port
+// returns (0, nil) when the timeout expires, which bufio would give up
+// on after a few idle minutes and report as a dropped connection.
+type serialConn struct {
+	serial.Port
+	rtscts bool // Wait for CTS before writing
+}
+
+// Read blocks until data arrives or the port fails (e.g. is unplugged or closed)
+func (c serialConn) Read(p []byte) (int, error) {
+	for {
+		n, err := c.Port.Read(p)
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// Write sends p, first waiting for the TNC to raise CTS if RTS/CTS
+// flow control is on. The serial driver has no hardware handshake
+// setting, so it is done here a write at a time.
+func (c serialConn) Write(p []byte) (int, error) {
+	if c.rtscts {
+		deadline := time.Now().Add(ctsTimeout)
+		for {
+			bits, err := c.Port.GetModemStatusBits()
+			if err != nil {
+				return 0, fmt.Errorf("failed to read CTS: %w", err)
+			}
+			if bits.CTS {
+				break
+			}
+			if time.Now().After(deadline) {
+				return 0, fmt.Errorf("timed out waiting for CTS from TNC")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return c.Port.Write(p)
+}
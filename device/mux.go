@@ -0,0 +1,103 @@
+package device
+
+import (
+	"packetmap/packet"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDedupeWindow is how long a repeat of a packet is dropped for
+// when the config doesn't say
+const DefaultDedupeWindow = 30 * time.Second
+
+// Mux runs several clients at once and merges their records into one
+// stream. The same packet heard again within the dedupe window on
+// another interface is dropped. Repeats on the interface that heard it
+// first are kept, so each digipeater hop still shows up.
+type Mux struct {
+	clients []Client
+	window  time.Duration // 0 turns deduplication off
+}
+
+// NewMux creates a mux over clients. Records keep the interface name
+// their client tagged them with.
+func NewMux(window time.Duration, clients ...Client) *Mux {
+	return &Mux{clients: clients, window: window}
+}
+
+// Start runs every client and forwards their records until all of
+// them have finished, then closes packetChan.
+// This function should be run as a goroutine.
+func (m *Mux) Start(packetChan chan<- *packet.Record) {
+	defer close(packetChan)
+
+	merged := make(chan *packet.Record)
+	var wg sync.WaitGroup
+	for _, client := range m.clients {
+		inner := make(chan *packet.Record)
+		go client.Start(inner)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range inner {
+				merged <- rec
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	seen := make(map[string]string) // Dedupe key -> interface that heard it first
+	var order []heardKey            // Keys in seen, oldest first
+	for rec := range merged {
+		if m.window > 0 {
+			// Forget anything older than the window
+			for len(order) > 0 && rec.Received.Sub(order[0].heard) > m.window {
+				delete(seen, order[0].key)
+				order = order[1:]
+			}
+
+			key := dedupeKey(rec.TNC2)
+			if iface, dup := seen[key]; dup {
+				if iface != rec.Interface {
+					continue
+				}
+			} else {
+				seen[key] = rec.Interface
+				order = append(order, heardKey{key: key, heard: rec.Received})
+			}
+		}
+		packetChan <- rec
+	}
+}
+
+// heardKey records when a dedupe key was first heard, for pruning
+type heardKey struct {
+	key   string
+	heard time.Time
+}
+
+// dedupeKey identifies a packet by source, destination and payload.
+// The path is left out: it differs between RF and APRS-IS and changes
+// with every digipeater hop.
+func dedupeKey(tnc2 string) string {
+	header, payload, ok := strings.Cut(tnc2, ":")
+	if !ok {
+		return tnc2
+	}
+	if i := strings.IndexByte(header, ','); i != -1 {
+		header = header[:i]
+	}
+	return header + ":" + strings.TrimRight(payload, " ")
+}
+
+// Close closes every client
+func (m *Mux) Close() {
+	for _, client := range m.clients {
+		client.Close()
+	}
+}
@@ -0,0 +1,212 @@
+// Package device holds the TNC and network clients (in its
+// subpackages) and the supervisor that keeps them connected.
+package device
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"sync"
+	"time"
+)
+
+// Reconnect backoff limits
+const (
+	minBackoff = 1 * time.Second
+	maxBackoff = 2 * time.Minute
+
+	// A connection that stays up this long resets the backoff
+	stableAfter = 1 * time.Minute
+)
+
+// State is the connection state of an interface
+type State int
+
+const (
+	StateConnecting   State = iota // First connection attempt
+	StateConnected                 // Link is up
+	StateReconnecting              // Link lost or attempt failed, retrying after a delay
+	StateFailed                    // Gave up after too many failed attempts
+)
+
+func (s State) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// StateEvent reports a change in an interface's connection
+type StateEvent struct {
+	Interface string        // Interface name, as used to tag records
+	State     State         // New state
+	Err       error         // Why the link went down, for Reconnecting and Failed
+	Retry     time.Duration // Delay before the next attempt, for Reconnecting
+}
+
+// Client is a connected TNC or network client. Start reads until the
+// connection drops, then closes the channel.
+type Client interface {
+	Start(chan<- *packet.Record)
+	Close()
+}
+
+// ConnectFunc opens a new connection to an interface
+type ConnectFunc func() (Client, error)
+
+// Supervisor keeps an interface connected, redialling with exponential
+// backoff whenever the link drops. It is itself a Client: records from
+// every connection go down the one channel, which is only closed when
+// the supervisor is closed or gives up.
+type Supervisor struct {
+	name       string
+	connect    ConnectFunc
+	maxRetries int               // Failed attempts in a row before giving up, 0 for never
+	states     chan<- StateEvent // Where state changes are reported, may be nil
+
+	mu        sync.Mutex
+	client    Client // Current connection, nil while disconnected
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSupervisor creates a supervisor for the named interface. Nothing
+// is dialled until Start is called.
+func NewSupervisor(name string, connect ConnectFunc, maxRetries int, states chan<- StateEvent) *Supervisor {
+	return &Supervisor{
+		name:       name,
+		connect:    connect,
+		maxRetries: maxRetries,
+		states:     states,
+		done:       make(chan struct{}),
+	}
+}
+
+// Start connects and forwards records until Close is called or the
+// retry limit is reached, then closes packetChan.
+// This function should be run as a goroutine.
+func (s *Supervisor) Start(packetChan chan<- *packet.Record) {
+	defer close(packetChan)
+
+	backoff := minBackoff
+	failures := 0
+	s.emit(StateEvent{State: StateConnecting})
+
+	for {
+		client, err := s.connect()
+		if err == nil {
+			if !s.setClient(client) {
+				client.Close() // Closed while we were dialling
+				return
+			}
+			s.emit(StateEvent{State: StateConnected})
+
+			up := time.Now()
+			s.forward(client, packetChan)
+			s.setClient(nil)
+			client.Close() // Release whatever the dead link still holds
+			if s.isClosed() {
+				return
+			}
+
+			err = fmt.Errorf("connection lost")
+			if time.Since(up) >= stableAfter {
+				backoff = minBackoff
+				failures = 0
+			}
+		}
+
+		failures++
+		if s.maxRetries > 0 && failures > s.maxRetries {
+			s.emit(StateEvent{State: StateFailed, Err: err})
+			return
+		}
+		s.emit(StateEvent{State: StateReconnecting, Err: err, Retry: backoff})
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// forward runs one connection, passing its records on, tagged with
+// the interface name, until it closes
+func (s *Supervisor) forward(client Client, packetChan chan<- *packet.Record) {
+	inner := make(chan *packet.Record)
+	go client.Start(inner)
+
+	for rec := range inner {
+		rec.Interface = s.name
+		select {
+		case packetChan <- rec:
+		case <-s.done:
+			// Nobody is listening any more; let the client wind down
+			go func() {
+				for range inner {
+				}
+			}()
+			return
+		}
+	}
+}
+
+// setClient records the current connection. It returns false if the
+// supervisor has already been closed.
+func (s *Supervisor) setClient(client Client) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isClosed() {
+		return false
+	}
+	s.client = client
+	return true
+}
+
+// Client returns the current connection, or nil while disconnected
+func (s *Supervisor) Client() Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+func (s *Supervisor) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// emit reports a state change, tagged with the interface name
+func (s *Supervisor) emit(ev StateEvent) {
+	if s.states == nil {
+		return
+	}
+	ev.Interface = s.name
+	select {
+	case s.states <- ev:
+	case <-s.done:
+	}
+}
+
+// Close stops reconnecting and closes the current connection
+func (s *Supervisor) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		close(s.done)
+		if s.client != nil {
+			s.client.Close()
+		}
+	})
+}
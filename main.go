@@ -1,19 +1,28 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os/exec" // --- ADDED ---
+	"packetmap/aprs"
 	"packetmap/config"
+	"packetmap/device"
+	"packetmap/device/agw"
 	"packetmap/device/aprsis"
 	"packetmap/device/kiss"
 	"packetmap/packet"
+	"packetmap/ui/bulletin"
 	"packetmap/ui/footer"
 	"packetmap/ui/header"
 	mapview "packetmap/ui/map"
 	"packetmap/ui/msgbar"
+	"packetmap/ui/prompt"
+	"packetmap/ui/rawlog"
 	"packetmap/ui/sidebar"
+	telemetryview "packetmap/ui/telemetry"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -24,10 +33,53 @@ const mapShapePath = "mapdata/ne_10m_admin_1_states_provinces.shp"
 
 // PacketClient defines the interface for TNC/network clients
 type PacketClient interface {
-	Start(chan<- *packet.Packet)
+	Start(chan<- *packet.Record)
 	Close()
 }
 
+// panel selects what is shown in the main area next to the sidebar
+type panel int
+
+const (
+	panelMap       panel = iota // The map (default)
+	panelTelemetry              // Telemetry panel
+	panelRawLog                 // Raw frames and parse errors
+	panelBulletins              // Bulletin board
+)
+
+// followDelay lets the map settle before a follow filter is sent, so
+// holding down a pan key doesn't send a filter per step
+const followDelay = time.Second
+
+// healthInterval is how often the header's link health is refreshed
+const healthInterval = 2 * time.Second
+
+// healthTickMsg refreshes the link health shown in the header
+type healthTickMsg struct{}
+
+// quietAfter is how long a connected link can go without packets
+// before the header shows it in the warning colour
+const quietAfter = 30 * time.Second
+
+// minBeaconInterval keeps automatic beacons within APRS-IS etiquette
+const minBeaconInterval = 10 * time.Minute
+
+// beaconTickMsg sends the periodic position beacon
+type beaconTickMsg struct{}
+
+// sentMsg reports the result of transmitting to APRS-IS
+type sentMsg struct {
+	what string         // What was sent, for notices
+	msg  *packet.Packet // Our own message, to show in the bar, nil for beacons
+	err  error
+}
+
+// followMsg asks for the area filter to be sent if the map hasn't
+// moved again since it was scheduled
+type followMsg struct {
+	gen int
+}
+
 // --- Constants for Layout ---
 const (
 	sidebarWidth = 20
@@ -46,14 +98,30 @@ type model struct {
 	footerModel  footer.Model
 	sidebarModel sidebar.Model
 
+	telemetryModel telemetryview.Model
+	rawlogModel    rawlog.Model
+	bulletinModel  bulletin.Model
+	panel          panel // What the main area is showing
+	promptModel    prompt.Model
+
+	// APRS-IS interfaces, whose filter can be changed from the UI
+	aprsisSessions []*aprsis.Session
+	following      bool // Send an area filter for the map view as it moves
+	followGen      int  // Bumped on every map move, to debounce following
+
+	linkStates map[string]device.StateEvent // Latest state per interface
+
+	msgSeq int // Last message ID we sent
+
 	packetClient PacketClient
-	packetChan   chan *packet.Packet
+	packetChan   chan *packet.Record
+	stateChan    chan device.StateEvent // Connection state changes
 
 	err error
 }
 
 // initialModel creates the starting model
-func initialModel(conf config.Config, client PacketClient, pChan chan *packet.Packet) model {
+func initialModel(conf config.Config, client PacketClient, pChan chan *packet.Record, sChan chan device.StateEvent, sessions []*aprsis.Session) model {
 	mapMod, err := mapview.New(mapShapePath, conf)
 	if err != nil {
 		return model{err: err}
@@ -66,6 +134,13 @@ func initialModel(conf config.Config, client PacketClient, pChan chan *packet.Pa
 
 	footerMod.SetZoom(mapMod.GetZoomLevel())
 
+	following := false
+	for _, iface := range conf.AllInterfaces() {
+		if strings.ToUpper(iface.Type) == "APRSIS" && iface.APRSIS.Follow {
+			following = true
+		}
+	}
+
 	return model{
 		width:        80, // Default width
 		height:       60, // Default height
@@ -77,17 +152,106 @@ func initialModel(conf config.Config, client PacketClient, pChan chan *packet.Pa
 		sidebarModel: sidebarMod,
 		packetClient: client,
 		packetChan:   pChan,
+		stateChan:    sChan,
+
+		telemetryModel: telemetryview.New(),
+		rawlogModel:    rawlog.New(),
+		bulletinModel:  bulletin.New(time.Duration(conf.Bulletin.Expiry) * time.Minute),
+		promptModel:    prompt.New(),
+
+		aprsisSessions: sessions,
+		following:      following && len(sessions) > 0,
+		linkStates:     make(map[string]device.StateEvent),
+
+		// Recipients drop repeats of an ID they've seen from us, so
+		// start from the clock rather than 1 to avoid reusing the
+		// IDs of the last run
+		msgSeq: int(time.Now().Unix() % 99999),
 	}
 }
 
-// listenForPackets is a tea.Cmd that waits for the next packet
+// listenForPackets is a tea.Cmd that waits for the next received frame
 func (m model) listenForPackets() tea.Cmd {
 	return func() tea.Msg {
-		pkt := <-m.packetChan
-		if pkt == nil {
-			return fmt.Errorf("connection closed")
+		rec := <-m.packetChan
+		if rec == nil {
+			// The client has given up; the header shows why, and the
+			// map stays up with what we've heard so far
+			return nil
 		}
-		return pkt
+		return rec
+	}
+}
+
+// listenForStates is a tea.Cmd that waits for the next connection state change
+func (m model) listenForStates() tea.Cmd {
+	return func() tea.Msg {
+		return <-m.stateChan
+	}
+}
+
+// healthTick schedules the next link health refresh
+func healthTick() tea.Cmd {
+	return tea.Tick(healthInterval, func(time.Time) tea.Msg {
+		return healthTickMsg{}
+	})
+}
+
+// linkStatus describes a connection state for the header
+func linkStatus(ev device.StateEvent) (string, header.LinkLevel) {
+	switch ev.State {
+	case device.StateConnected:
+		return "connected", header.LinkUp
+	case device.StateReconnecting:
+		return fmt.Sprintf("reconnecting in %s (%v)", ev.Retry, ev.Err), header.LinkWait
+	case device.StateFailed:
+		return fmt.Sprintf("failed (%v)", ev.Err), header.LinkDown
+	}
+	return ev.State.String(), header.LinkWait
+}
+
+// healthStatus describes a connected APRS-IS link for the header:
+// server, login, packet rate and how long since data and keepalives
+func healthStatus(h aprsis.Health, now time.Time) (string, header.LinkLevel) {
+	since := func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return now.Sub(t).Round(time.Second).String()
+	}
+
+	server := h.Server
+	if h.Software != "" {
+		server += " (" + h.Software + ")"
+	}
+	login := "unverified"
+	if h.Verified {
+		login = "verified"
+	}
+	status := fmt.Sprintf("%s %s, %d/min, data %s, keepalive %s",
+		strings.TrimSpace(server), login, h.PacketsPerMin, since(h.LastData), since(h.LastKeepalive))
+
+	// Keepalives keep coming on a quiet filter, so only warn when
+	// both have stopped
+	if now.Sub(h.LastData) > quietAfter && now.Sub(h.LastKeepalive) > quietAfter {
+		return status, header.LinkWait
+	}
+	return status, header.LinkUp
+}
+
+// refreshLinks redraws every interface's status in the header
+func (m *model) refreshLinks() {
+	now := time.Now()
+	for name, ev := range m.linkStates {
+		status, level := linkStatus(ev)
+		if ev.State == device.StateConnected {
+			for _, s := range m.aprsisSessions {
+				if h, ok := s.Health(); ok && s.Name() == name {
+					status, level = healthStatus(h, now)
+				}
+			}
+		}
+		m.headerModel.SetLink(name, status, level)
 	}
 }
 
@@ -105,7 +269,125 @@ func speakMessageCmd(msg string) tea.Cmd {
 
 func (m model) Init() tea.Cmd {
 	go m.packetClient.Start(m.packetChan)
-	return m.listenForPackets()
+	cmds := []tea.Cmd{m.listenForPackets(), m.listenForStates(), healthTick()}
+	if m.config.Station.Beacon > 0 && len(m.aprsisSessions) > 0 {
+		cmds = append(cmds, m.beaconTick())
+	}
+	if m.following {
+		// Send the area filter for the starting view
+		cmds = append(cmds, func() tea.Msg { return followMsg{gen: m.followGen} })
+	}
+	return tea.Batch(cmds...)
+}
+
+// sendToAPRSIS transmits a payload from our callsign through the first
+// APRS-IS interface that will take it. Unverified logins refuse.
+func (m model) sendToAPRSIS(what, payload string, msg *packet.Packet) tea.Cmd {
+	sessions := m.aprsisSessions
+	source := strings.ToUpper(m.config.Station.Callsign)
+	return func() tea.Msg {
+		err := fmt.Errorf("no APRS-IS interface configured")
+		for _, s := range sessions {
+			if err = s.Send(source, aprs.ToCall, nil, payload); err == nil {
+				break
+			}
+		}
+		return sentMsg{what: what, msg: msg, err: err}
+	}
+}
+
+// sendMessage parses "CALL text" from the message prompt and sends it
+// with the next message ID so the recipient can ack it
+func (m *model) sendMessage(input string) tea.Cmd {
+	to, text, ok := strings.Cut(strings.TrimSpace(input), " ")
+	text = strings.TrimSpace(text)
+	if !ok || text == "" {
+		m.msgbarModel.AddNotice("Message not sent: type the callsign, a space, then the message")
+		return nil
+	}
+
+	m.msgSeq = m.msgSeq%99999 + 1
+	id := fmt.Sprint(m.msgSeq)
+	payload, err := aprs.FormatMessage(to, text, id)
+	if err != nil {
+		m.msgbarModel.AddNotice("Message not sent: " + err.Error())
+		return nil
+	}
+
+	msg := &packet.Packet{
+		Type:     packet.TypeMessage,
+		MsgKind:  packet.MessageText,
+		Callsign: strings.ToUpper(m.config.Station.Callsign),
+		MsgTo:    strings.ToUpper(to),
+		MsgBody:  text,
+		MsgID:    id,
+	}
+	return m.sendToAPRSIS("Message to "+msg.MsgTo, payload, msg)
+}
+
+// sendBeacon sends our position, from the centre of the station
+// gridsquare, with the configured symbol and comment
+func (m *model) sendBeacon() tea.Cmd {
+	lon, lat, err := mapview.GridSquareToLatLon(m.config.Station.GridSquare)
+	if err != nil {
+		m.msgbarModel.AddNotice("Beacon not sent: " + err.Error())
+		return nil
+	}
+	symbol := m.config.Station.Symbol
+	if len(symbol) != 2 {
+		symbol = "/-" // House
+	}
+	payload := aprs.FormatPosition(lat, lon, symbol[0], symbol[1], m.config.Station.Comment)
+	return m.sendToAPRSIS("Beacon", payload, nil)
+}
+
+// beaconTick schedules the next automatic beacon
+func (m model) beaconTick() tea.Cmd {
+	interval := max(time.Duration(m.config.Station.Beacon)*time.Minute, minBeaconInterval)
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return beaconTickMsg{}
+	})
+}
+
+// setFilter sends a new filter to every APRS-IS interface. Failures are
+// logged; the filter is still used when the link comes back.
+func (m *model) setFilter(filter string) tea.Cmd {
+	m.footerModel.SetFilter(filter, m.following)
+	sessions := m.aprsisSessions
+	return func() tea.Msg {
+		for _, s := range sessions {
+			s.SetFilter(filter)
+		}
+		return nil
+	}
+}
+
+// mapMoved schedules an area filter for the new view when following
+func (m *model) mapMoved() tea.Cmd {
+	if !m.following {
+		return nil
+	}
+	m.followGen++
+	gen := m.followGen
+	return tea.Tick(followDelay, func(time.Time) tea.Msg {
+		return followMsg{gen: gen}
+	})
+}
+
+// areaFilter is the APRS-IS a/ filter for the map view
+func (m model) areaFilter() string {
+	north, west, south, east := m.mapModel.ViewBounds()
+	return fmt.Sprintf("a/%.3f/%.3f/%.3f/%.3f", north, west, south, east)
+}
+
+// togglePanel switches the main area to p, or back to the map if p is
+// already showing
+func (m *model) togglePanel(p panel) {
+	if m.panel == p {
+		m.panel = panelMap
+	} else {
+		m.panel = p
+	}
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -126,27 +408,86 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	)
 
 	switch msg := msg.(type) {
-	case *packet.Packet:
-		switch msg.Type {
-		case packet.TypePosition:
-			m.mapModel, mapCmd = m.mapModel.Update(msg)
-			m.footerModel.SetLastPacket(msg.Callsign)
-			m.sidebarModel.AddPacket(msg.Callsign)
-			cmds = append(cmds, mapCmd)
-
-		case packet.TypeMessage:
-			// --- THIS IS THE NEW LOGIC ---
-			if m.config.Msgbar.Say {
-				// Format a more natural-sounding message for speech
-				msgStr := fmt.Sprintf("Message from %s to %s: %s", msg.Callsign, msg.MsgTo, msg.MsgBody)
-				cmds = append(cmds, speakMessageCmd(msgStr))
+	case *packet.Record:
+		// Every frame goes to the raw log, parsed or not
+		m.rawlogModel.AddRecord(msg)
+
+		if pkt := msg.Packet; pkt != nil {
+			switch pkt.Type {
+			case packet.TypePosition, packet.TypeWeather:
+				m.mapModel, mapCmd = m.mapModel.Update(pkt)
+				m.footerModel.SetLastPacket(pkt.Callsign)
+				m.sidebarModel.AddPacket(pkt.Callsign)
+				cmds = append(cmds, mapCmd)
+
+			case packet.TypeMessage:
+				// --- THIS IS THE NEW LOGIC ---
+				if m.config.Msgbar.Say && pkt.MsgKind == packet.MessageText {
+					// Format a more natural-sounding message for speech
+					msgStr := fmt.Sprintf("Message from %s to %s: %s", pkt.Callsign, pkt.MsgTo, pkt.MsgBody)
+					cmds = append(cmds, speakMessageCmd(msgStr))
+				}
+				// --- END NEW LOGIC ---
+
+				m.msgbarModel, msgbarCmd = m.msgbarModel.Update(pkt)
+				cmds = append(cmds, msgbarCmd)
+
+			case packet.TypeTelemetry:
+				m.telemetryModel.AddPacket(pkt)
+
+			case packet.TypeStatus:
+				// A locator status puts a station without a position
+				// on the map at its grid square
+				m.mapModel, mapCmd = m.mapModel.Update(pkt)
+				m.sidebarModel.SetStatus(pkt.Callsign, pkt.Status)
+				m.sidebarModel.AddPacket(pkt.Callsign)
+				cmds = append(cmds, mapCmd)
+
+			case packet.TypeBulletin:
+				m.bulletinModel.AddPacket(pkt)
 			}
-			// --- END NEW LOGIC ---
+		}
+		cmds = append(cmds, m.listenForPackets())
 
-			m.msgbarModel, msgbarCmd = m.msgbarModel.Update(msg)
+	case followMsg:
+		// Only the latest move counts, and only if still following
+		if m.following && msg.gen == m.followGen {
+			cmds = append(cmds, m.setFilter(m.areaFilter()))
+		}
+
+	case prompt.SubmitMsg:
+		switch {
+		case msg.ID == "filter" && strings.TrimSpace(msg.Value) != "":
+			// A hand-written filter stops the map following
+			m.following = false
+			cmds = append(cmds, m.setFilter(strings.TrimSpace(msg.Value)))
+		case msg.ID == "message":
+			cmds = append(cmds, m.sendMessage(msg.Value))
+		}
+
+	case sentMsg:
+		switch {
+		case msg.err != nil:
+			m.msgbarModel.AddNotice(fmt.Sprintf("%s not sent: %v", msg.what, msg.err))
+		case msg.msg != nil:
+			// Show our message; its ack will mark it
+			m.msgbarModel, msgbarCmd = m.msgbarModel.Update(msg.msg)
 			cmds = append(cmds, msgbarCmd)
+		default:
+			m.msgbarModel.AddNotice(msg.what + " sent to APRS-IS")
 		}
-		cmds = append(cmds, m.listenForPackets())
+
+	case beaconTickMsg:
+		cmds = append(cmds, m.sendBeacon(), m.beaconTick())
+
+	case device.StateEvent:
+		m.linkStates[msg.Interface] = msg
+		m.refreshLinks()
+		cmds = append(cmds, m.listenForStates())
+
+	case healthTickMsg:
+		m.refreshLinks()
+		cmds = append(cmds, healthTick())
 
 	case error:
 		m.err = msg
@@ -173,23 +514,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		mapMsg := tea.WindowSizeMsg{Width: mapWidth, Height: mainHeight}
 		m.mapModel, mapCmd = m.mapModel.Update(mapMsg)
+		m.telemetryModel, _ = m.telemetryModel.Update(mapMsg)
+		m.rawlogModel, _ = m.rawlogModel.Update(mapMsg)
+		m.bulletinModel, _ = m.bulletinModel.Update(mapMsg)
 
 		msgbarMsg := tea.WindowSizeMsg{Width: m.width, Height: msgbarHeight}
 		m.msgbarModel, msgbarCmd = m.msgbarModel.Update(msgbarMsg)
 
 		footerMsg := tea.WindowSizeMsg{Width: m.width, Height: footerHeight}
 		m.footerModel, footerCmd = m.footerModel.Update(footerMsg)
+		m.promptModel, _ = m.promptModel.Update(footerMsg)
 
 		cmds = append(cmds, headerCmd, sidebarCmd, mapCmd, msgbarCmd, footerCmd)
 
 	case tea.KeyMsg:
+		// An open prompt gets every key
+		if m.promptModel.Active() {
+			var promptCmd tea.Cmd
+			m.promptModel, promptCmd = m.promptModel.Update(msg)
+			return m, promptCmd
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c", "esc":
 			return m, tea.Quit
+		case "t":
+			m.togglePanel(panelTelemetry)
+		case "d":
+			m.togglePanel(panelRawLog)
+		case "b":
+			m.togglePanel(panelBulletins)
+		case "f":
+			if len(m.aprsisSessions) > 0 {
+				m.promptModel.Open("filter", "APRS-IS filter", m.aprsisSessions[0].Filter())
+			}
+		case "m":
+			if len(m.aprsisSessions) > 0 {
+				m.promptModel.Open("message", "Message (CALL text)", "")
+			}
+		case "p":
+			if len(m.aprsisSessions) > 0 {
+				cmds = append(cmds, m.sendBeacon())
+			}
+		case "a":
+			if len(m.aprsisSessions) > 0 {
+				m.following = !m.following
+				m.footerModel.SetFilter(m.aprsisSessions[0].Filter(), m.following)
+				cmds = append(cmds, m.mapMoved())
+			}
 		default:
+			north, west, south, east := m.mapModel.ViewBounds()
 			m.mapModel, mapCmd = m.mapModel.Update(msg)
 			cmds = append(cmds, mapCmd)
+			// Only pans, zooms and resets move the view; label changes
+			// and stray keys mustn't re-send the filter
+			if n, w, s, e := m.mapModel.ViewBounds(); n != north || w != west || s != south || e != east {
+				cmds = append(cmds, m.mapMoved())
+			}
 			m.footerModel.SetZoom(m.mapModel.GetZoomLevel())
+			m.footerModel.SetLabelMode(m.mapModel.LabelMode())
 		}
 
 	default:
@@ -221,9 +604,22 @@ func (m model) View() string {
 
 	headerView := m.headerModel.View()
 	sidebarView := m.sidebarModel.View()
-	mapView := m.mapModel.View()
+	var mapView string
+	switch m.panel {
+	case panelTelemetry:
+		mapView = m.telemetryModel.View()
+	case panelRawLog:
+		mapView = m.rawlogModel.View()
+	case panelBulletins:
+		mapView = m.bulletinModel.View()
+	default:
+		mapView = m.mapModel.View()
+	}
 	msgbarView := m.msgbarModel.View()
 	footerView := m.footerModel.View()
+	if m.promptModel.Active() {
+		footerView = m.promptModel.View()
+	}
 
 	middleStack := lipgloss.JoinHorizontal(lipgloss.Top,
 		sidebarView,
@@ -238,39 +634,114 @@ func (m model) View() string {
 	)
 }
 
+// connectFunc returns how to dial one configured interface
+// APRS-IS interfaces get a session, returned so the UI can change
+// their filter.
+func connectFunc(conf config.Config, iface config.InterfaceConfig) (device.ConnectFunc, *aprsis.Session, error) {
+	switch strings.ToUpper(iface.Type) {
+	case "KISS":
+		return func() (device.Client, error) {
+			return kiss.Connect(iface)
+		}, nil, nil
+	case "APRSIS":
+		// APRSIS Connect needs the full config to get callsign
+		session := aprsis.NewSession(conf, iface)
+		return func() (device.Client, error) {
+			return session.Connect()
+		}, session, nil
+	case "AGW":
+		return func() (device.Client, error) {
+			return agw.Connect(conf, iface)
+		}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unknown interface type in config: %s", iface.Type)
+}
+
+// probeListen is how long -probe watches each serial port for KISS frames
+const probeListen = 5 * time.Second
+
+// probeSerial looks for KISS TNCs on the serial ports and prints what
+// it finds, using the serial settings of the first KISS interface
+func probeSerial(conf config.Config) {
+	var serialConf config.SerialConfig
+	for _, iface := range conf.AllInterfaces() {
+		if strings.ToUpper(iface.Type) == "KISS" {
+			serialConf = iface.Serial
+			break
+		}
+	}
+
+	fmt.Println("Probing serial ports for KISS TNCs...")
+	results, err := kiss.Probe(serialConf, probeListen)
+	if err != nil {
+		log.Fatalf("Probe failed: %v", err)
+	}
+	if len(results) == 0 {
+		fmt.Println("No serial ports found")
+	}
+	for _, r := range results {
+		mark := " "
+		if r.KISS {
+			mark = "*"
+		}
+		fmt.Printf("%s %s %s\n    %s\n", mark, r.Port, r.Description, r.Verdict)
+	}
+}
+
 func main() {
+	probe := flag.Bool("probe", false, "look for KISS TNCs on the serial ports and exit")
+	flag.Parse()
+
 	// Load Config
 	conf, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config.toml: %v", err)
 	}
 
-	// --- UPDATED: Connect based on config type ---
-	var packetClient PacketClient // Use the interface
-	var connectErr error
+	if *probe {
+		probeSerial(conf)
+		return
+	}
 
-	switch strings.ToUpper(conf.Interface.Type) {
-	case "KISS":
-		packetClient, connectErr = kiss.Connect(conf.Interface)
-	case "APRSIS":
-		// APRSIS Connect needs the full config to get callsign
-		// --- THIS IS THE FIX ---
-		packetClient, connectErr = aprsis.Connect(conf)
-		// --- END FIX ---
-	default:
-		connectErr = fmt.Errorf("unknown interface type in config: %s", conf.Interface.Type)
+	interfaces := conf.AllInterfaces()
+	if len(interfaces) == 0 {
+		log.Fatalf("No interface configured in config.toml")
+	}
+
+	// Create packet and connection state channels
+	packetChan := make(chan *packet.Record)
+	stateChan := make(chan device.StateEvent)
+
+	// --- UPDATED: Connect based on config type ---
+	// Each interface gets a supervisor that dials in the background and
+	// redials if the link drops; the mux merges them into one stream
+	clients := make([]device.Client, 0, len(interfaces))
+	var sessions []*aprsis.Session
+	for _, iface := range interfaces {
+		connect, session, err := connectFunc(conf, iface)
+		if err != nil {
+			log.Fatalf("Failed to connect to interface: %v", err)
+		}
+		if session != nil {
+			sessions = append(sessions, session)
+		}
+		clients = append(clients, device.NewSupervisor(iface.DisplayName(), connect, iface.MaxRetries, stateChan))
 	}
 
-	if connectErr != nil {
-		log.Fatalf("Failed to connect to interface: %v", connectErr)
+	dedupe := time.Duration(conf.Dedupe) * time.Second
+	if conf.Dedupe == 0 {
+		dedupe = device.DefaultDedupeWindow
 	}
-	defer packetClient.Close() // Close whichever client we connected
+	var packetClient PacketClient = device.NewMux(dedupe, clients...)
+	defer packetClient.Close() // Close every client we connected
 
-	// Create packet channel
-	packetChan := make(chan *packet.Packet)
+	// Connection logging would draw over the TUI, so send it to a file
+	if logFile, err := tea.LogToFile("packetmap.log", ""); err == nil {
+		defer logFile.Close()
+	}
 
 	// Run Bubble Tea
-	p := tea.NewProgram(initialModel(conf, packetClient, packetChan), tea.WithAltScreen())
+	p := tea.NewProgram(initialModel(conf, packetClient, packetChan, stateChan, sessions), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		log.Fatalf("Alas, there's been an error: %v", err)
 	}
@@ -1,28 +1,199 @@
 package packet
 
+import "time"
+
 // --- NEW ---
 // PacketType defines the type of APRS data.
 type PacketType int
 
 const (
-	TypePosition PacketType = iota // A position report
-	TypeMessage                    // A message
-	TypeUnknown                    // Unknown or unparsed
+	TypePosition  PacketType = iota // A position report
+	TypeMessage                     // A message
+	TypeWeather                     // A weather report, with or without position
+	TypeTelemetry                   // A telemetry frame or PARM/UNIT/EQNS/BITS definition
+	TypeStatus                      // A status report
+	TypeBulletin                    // A bulletin or announcement (BLNx, NWS)
+	TypeUnknown                     // Unknown or unparsed
 )
 
 // --- END NEW ---
 
+// MessageKind tells text messages from acknowledgements and rejects.
+type MessageKind int
+
+const (
+	MessageText   MessageKind = iota // An ordinary text message
+	MessageAck                       // "ackID": message ID was received
+	MessageReject                    // "rejID": message ID was rejected
+)
+
+// BulletinKind tells bulletins, announcements and NWS feeds apart.
+type BulletinKind int
+
+const (
+	BulletinGeneral      BulletinKind = iota // BLN0-BLN9
+	BulletinAnnouncement                     // BLNA-BLNZ
+	BulletinGroup                            // BLN#GROUP, e.g. BLN3WX
+	BulletinNWS                              // National Weather Service and similar feeds
+)
+
+// String returns a short label for the kind
+func (k BulletinKind) String() string {
+	switch k {
+	case BulletinAnnouncement:
+		return "ANN"
+	case BulletinGroup:
+		return "GRP"
+	case BulletinNWS:
+		return "NWS"
+	default:
+		return "BLN"
+	}
+}
+
+// Digipeater is one hop of a packet's path.
+type Digipeater struct {
+	Callsign string // Callsign-SSID or alias, e.g. "WIDE2-1"
+	Repeated bool   // Has-been-repeated (H) bit, shown as '*' in TNC2 text
+}
+
+// String renders the hop in TNC2 form, e.g. "N0DIG*"
+func (d Digipeater) String() string {
+	if d.Repeated {
+		return d.Callsign + "*"
+	}
+	return d.Callsign
+}
+
+// PHG describes a station's power, antenna height and gain.
+type PHG struct {
+	Power       int // Watts
+	Height      int // Feet above average terrain
+	Gain        int // dB
+	Directivity int // Degrees, 0 for omni-directional
+}
+
+// DFS describes a direction-finding report: received signal strength
+// and the antenna used to take it.
+type DFS struct {
+	Strength    int // S-points (0-9)
+	Height      int // Feet above average terrain
+	Gain        int // dB
+	Directivity int // Degrees, 0 for omni-directional
+}
+
+// Weather holds a weather report in the units APRS sends them in.
+// Fields are nil when the station doesn't report them.
+type Weather struct {
+	WindDirection *float64 // Degrees
+	WindSpeed     *float64 // mph, sustained one-minute
+	WindGust      *float64 // mph, peak in the last 5 minutes
+	Temperature   *float64 // Fahrenheit
+	Rain1h        *float64 // Inches in the last hour
+	Rain24h       *float64 // Inches in the last 24 hours
+	RainMidnight  *float64 // Inches since local midnight
+	Humidity      *float64 // Percent
+	Pressure      *float64 // Millibars (hPa)
+	Luminosity    *float64 // W/m^2
+	Snow          *float64 // Inches in the last 24 hours
+}
+
+// Telemetry holds one raw T# telemetry frame.
+type Telemetry struct {
+	Sequence   string    // Sequence number, usually 000-999
+	Analog     []float64 // Up to 5 raw analog values
+	Digital    [8]bool   // Digital bits B1-B8
+	HasDigital bool      // Digital bits were present in the frame
+	Comment    string    // Text after the digital bits
+}
+
+// TelemetryMeta holds a PARM, UNIT, EQNS or BITS message describing a
+// station's telemetry channels.
+type TelemetryMeta struct {
+	Station string   // Station the definition applies to (message addressee)
+	Kind    string   // "PARM", "UNIT", "EQNS" or "BITS"
+	Values  []string // Comma-separated fields after the keyword
+}
+
 // Packet holds the simplified APRS data we care about.
 type Packet struct {
-	Callsign string     // Source callsign (always present)
+	Callsign string     // Source callsign, or the object/item name (always present)
 	Type     PacketType // --- NEW: Packet type ---
 
+	// Header fields
+	Destination string       // Destination callsign-SSID, e.g. "APRS" or a Mic-E address
+	Path        []Digipeater // Digipeater path in order
+	QConstruct  string       // APRS-IS q-construct, e.g. "qAR", empty for RF frames
+	IGate       string       // Station named after the q-construct (the igate for qAR/qAO)
+
+	// Set when the packet arrived wrapped in a third-party ('}') header
+	ThirdPartyGateway string       // Station that injected the packet (outer source)
+	ThirdPartyPath    []Digipeater // Outer path the gateway sent it with
+
 	// Fields for TypePosition
-	Lat float64
-	Lon float64
+	Lat       float64
+	Lon       float64
+	Timestamp time.Time // When the position was fixed, if the sender included it
+
+	// Map symbol
+	SymbolTable   byte // '/' primary or '\' alternate table
+	Symbol        byte // Symbol code within the table, e.g. '>' for a car
+	SymbolOverlay byte // Overlay character on alternate symbols (0-9, A-Z), 0 if none
+
+	// Optional position data (zero when not reported)
+	Course          int     // Course over ground in degrees
+	Speed           float64 // Speed in knots
+	Altitude        float64 // Altitude in feet
+	RadioRange      float64 // Pre-calculated radio range in miles
+	Compressed      bool    // Position was sent in Base-91 compressed format
+	CompressionType byte    // Compressed position 'T' byte (minus 33)
+	PHG             *PHG    // Power-height-gain, nil if not reported
+	DFS             *DFS    // Direction-finding signal strength, nil if not reported
+	DAODatum        byte    // Datum letter of a !DAO! precision extension, 0 if none
+	Comment         string  // Free-text comment left after extensions are removed
+
+	// Fields for TypeWeather (also set on position+weather reports)
+	Weather *Weather
+
+	// Fields for TypeStatus
+	Status        string // Status text
+	StatusLocator string // Maidenhead locator from a locator status, e.g. "IO91SX"
+	BeamHeading   int    // Beam heading in degrees, -1 if not reported
+	ERP           int    // Effective radiated power in watts from the ^HP suffix
+
+	// Fields for TypeTelemetry (one of the two is set)
+	Telemetry     *Telemetry
+	TelemetryMeta *TelemetryMeta
+
+	// Fields for objects and items
+	Originator string // Station that sent the object/item, empty for station positions
+	Killed     bool   // The object/item has been killed and should be removed
+
+	// Fields for Mic-E positions
+	MicEMessage string // Mic-E message code, e.g. "En Route"
+	Device      string // Radio model, e.g. "Kenwood TH-D72"
 
 	// Fields for TypeMessage
-	MsgTo   string // Recipient
-	MsgBody string // Message content
-	MsgID   string // Message ID
-}
\ No newline at end of file
+	MsgTo       string      // Recipient
+	MsgBody     string      // Message content (empty for acks/rejects)
+	MsgID       string      // Message ID; for acks/rejects, the ID being answered
+	MsgKind     MessageKind // Text, ack or reject
+	MsgReplyAck string      // Reply-ack ("{MM}AA"): ID of an earlier message this one acknowledges
+
+	// Fields for TypeBulletin (MsgTo and MsgBody hold addressee and text)
+	BulletinKind  BulletinKind
+	BulletinID    string // "0"-"9" or "A"-"Z"; the full addressee for NWS
+	BulletinGroup string // Group name for group bulletins, e.g. "WX"
+}
+
+// Record is one frame exactly as received from an interface, kept
+// whether or not it parsed so undecodable traffic can be inspected.
+type Record struct {
+	Raw       []byte    // Frame bytes: raw AX.25 from a TNC, a text line from APRS-IS
+	TNC2      string    // Frame rendered as a TNC2 line (SRC>DEST,PATH:payload)
+	Received  time.Time // When the frame arrived
+	Interface string    // Interface it arrived on
+	Port      int       // KISS port (0-15) on multi-port TNCs, 0 otherwise
+	Packet    *Packet   // Parsed packet, nil if parsing failed
+	Err       error     // Why parsing failed, nil on success
+}
@@ -0,0 +1,195 @@
+package telemetry
+
+import (
+	"packetmap/packet"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Number of analog and digital channels in an APRS telemetry frame
+const (
+	analogChannels  = 5
+	digitalChannels = 8
+)
+
+// Definition describes a station's telemetry channels, built up from its
+// PARM, UNIT, EQNS and BITS messages.
+type Definition struct {
+	Names   [analogChannels + digitalChannels]string // Channel names (PARM)
+	Units   [analogChannels + digitalChannels]string // Units or bit labels (UNIT)
+	Coeffs  [analogChannels][3]float64               // a, b, c for a*x^2 + b*x + c (EQNS)
+	Sense   [digitalChannels]bool                    // Bit value that means "on" (BITS)
+	Project string                                   // Project title (BITS)
+}
+
+// newDefinition returns a definition with the identity equation
+// (0, 1, 0) on every analog channel, as the spec requires before an
+// EQNS message has been heard.
+func newDefinition() Definition {
+	var d Definition
+	for i := range d.Coeffs {
+		d.Coeffs[i] = [3]float64{0, 1, 0}
+	}
+	for i := range d.Sense {
+		d.Sense[i] = true
+	}
+	return d
+}
+
+// Scale applies the channel's equation to a raw analog value.
+func (d Definition) Scale(channel int, raw float64) float64 {
+	if channel < 0 || channel >= analogChannels {
+		return raw
+	}
+	a, b, c := d.Coeffs[channel][0], d.Coeffs[channel][1], d.Coeffs[channel][2]
+	return a*raw*raw + b*raw + c
+}
+
+// Sample is one received telemetry frame with the equations applied.
+type Sample struct {
+	Time       time.Time
+	Sequence   string
+	Raw        []float64
+	Values     []float64 // Raw values scaled with the station's EQNS
+	Digital    [digitalChannels]bool
+	HasDigital bool
+}
+
+// Station holds the telemetry definition and history for one callsign.
+type Station struct {
+	Callsign   string
+	Definition Definition
+	History    []Sample // Oldest first
+	LastHeard  time.Time
+}
+
+// Latest returns the most recent sample, or nil if none has arrived yet
+// (a station may send its definitions first).
+func (s *Station) Latest() *Sample {
+	if len(s.History) == 0 {
+		return nil
+	}
+	return &s.History[len(s.History)-1]
+}
+
+// Store keeps telemetry for every station heard.
+type Store struct {
+	stations   map[string]*Station
+	maxHistory int
+}
+
+// NewStore creates a store that keeps up to maxHistory samples per station
+func NewStore(maxHistory int) *Store {
+	if maxHistory < 1 {
+		maxHistory = 1
+	}
+	return &Store{
+		stations:   make(map[string]*Station),
+		maxHistory: maxHistory,
+	}
+}
+
+// station returns the entry for a callsign, creating it if needed
+func (s *Store) station(callsign string) *Station {
+	st, ok := s.stations[callsign]
+	if !ok {
+		st = &Station{Callsign: callsign, Definition: newDefinition()}
+		s.stations[callsign] = st
+	}
+	return st
+}
+
+// Add records a TypeTelemetry packet, either a frame or a definition.
+func (s *Store) Add(pkt *packet.Packet) {
+	now := time.Now()
+
+	switch {
+	case pkt.Telemetry != nil:
+		st := s.station(pkt.Callsign)
+		tlm := pkt.Telemetry
+		sample := Sample{
+			Time:       now,
+			Sequence:   tlm.Sequence,
+			Raw:        tlm.Analog,
+			Values:     make([]float64, len(tlm.Analog)),
+			Digital:    tlm.Digital,
+			HasDigital: tlm.HasDigital,
+		}
+		for i, raw := range tlm.Analog {
+			sample.Values[i] = st.Definition.Scale(i, raw)
+		}
+		st.History = append(st.History, sample)
+		if len(st.History) > s.maxHistory {
+			st.History = st.History[len(st.History)-s.maxHistory:]
+		}
+		st.LastHeard = now
+
+	case pkt.TelemetryMeta != nil:
+		meta := pkt.TelemetryMeta
+		st := s.station(meta.Station)
+		st.Definition.apply(meta)
+		st.LastHeard = now
+		// Re-scale history so old samples match the new equations
+		if meta.Kind == "EQNS" {
+			for i := range st.History {
+				for ch, raw := range st.History[i].Raw {
+					st.History[i].Values[ch] = st.Definition.Scale(ch, raw)
+				}
+			}
+		}
+	}
+}
+
+// apply merges one PARM/UNIT/EQNS/BITS message into the definition.
+func (d *Definition) apply(meta *packet.TelemetryMeta) {
+	switch meta.Kind {
+	case "PARM":
+		for i, v := range meta.Values {
+			if i < len(d.Names) {
+				d.Names[i] = v
+			}
+		}
+	case "UNIT":
+		for i, v := range meta.Values {
+			if i < len(d.Units) {
+				d.Units[i] = v
+			}
+		}
+	case "EQNS":
+		for i, v := range meta.Values {
+			ch, coeff := i/3, i%3
+			if ch >= analogChannels {
+				break
+			}
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				d.Coeffs[ch][coeff] = f
+			}
+		}
+	case "BITS":
+		if len(meta.Values) == 0 {
+			return
+		}
+		bits := meta.Values[0]
+		for i := 0; i < digitalChannels && i < len(bits); i++ {
+			d.Sense[i] = bits[i] == '1'
+		}
+		// The project title follows the bits, e.g. "BITS.11111111,Solar site"
+		if len(meta.Values) > 1 {
+			d.Project = strings.Join(meta.Values[1:], ",")
+		}
+	}
+}
+
+// Stations returns all stations with telemetry, most recently heard first.
+func (s *Store) Stations() []*Station {
+	list := make([]*Station, 0, len(s.stations))
+	for _, st := range s.stations {
+		list = append(list, st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastHeard.After(list[j].LastHeard)
+	})
+	return list
+}
@@ -0,0 +1,143 @@
+package bulletin
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultExpiry is used when the config doesn't set one
+const defaultExpiry = 4 * time.Hour
+
+var ageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+// entry is the latest version of one bulletin
+type entry struct {
+	pkt      *packet.Packet
+	received time.Time
+}
+
+// Model holds the bulletin board's state
+type Model struct {
+	width   int
+	height  int
+	expiry  time.Duration
+	entries map[string]entry // Keyed by sender + addressee
+}
+
+// New creates a new bulletin board. Bulletins not refreshed within
+// expiry are dropped; zero uses the default.
+func New(expiry time.Duration) Model {
+	if expiry <= 0 {
+		expiry = defaultExpiry
+	}
+	return Model{
+		width:   80,
+		height:  23,
+		expiry:  expiry,
+		entries: make(map[string]entry),
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// AddPacket stores a TypeBulletin packet, replacing any earlier version
+// of the same bulletin from the same sender
+func (m *Model) AddPacket(pkt *packet.Packet) {
+	now := time.Now()
+	m.entries[pkt.Callsign+">"+pkt.MsgTo] = entry{pkt: pkt, received: now}
+
+	// Prune expired bulletins so the board doesn't grow forever
+	for key, e := range m.entries {
+		if now.Sub(e.received) > m.expiry {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// current returns the unexpired bulletins, announcements first, then
+// bulletins, group bulletins and NWS, each ordered by ID
+func (m Model) current(now time.Time) []entry {
+	var list []entry
+	for _, e := range m.entries {
+		if now.Sub(e.received) <= m.expiry {
+			list = append(list, e)
+		}
+	}
+
+	order := map[packet.BulletinKind]int{
+		packet.BulletinAnnouncement: 0,
+		packet.BulletinGeneral:      1,
+		packet.BulletinGroup:        2,
+		packet.BulletinNWS:          3,
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].pkt, list[j].pkt
+		if order[a.BulletinKind] != order[b.BulletinKind] {
+			return order[a.BulletinKind] < order[b.BulletinKind]
+		}
+		if a.MsgTo != b.MsgTo {
+			return a.MsgTo < b.MsgTo
+		}
+		return a.Callsign < b.Callsign
+	})
+	return list
+}
+
+func (m Model) View() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Width(m.width-2).   // -2 for border
+		Height(m.height-2). // -2 for border
+		Padding(0, 1)
+
+	contentWidth := m.width - 2 - 2 // -border, -padding
+	contentHeight := m.height - 2
+	if contentWidth < 1 {
+		contentWidth = 1
+	}
+	if contentHeight < 0 {
+		contentHeight = 0
+	}
+
+	now := time.Now()
+	lines := []string{lipgloss.NewStyle().Bold(true).Underline(true).Render("Bulletins")}
+	entries := m.current(now)
+	if len(entries) == 0 {
+		lines = append(lines, "No bulletins heard yet")
+	}
+	for _, e := range entries {
+		pkt := e.pkt
+		tag := fmt.Sprintf("[%s %s]", pkt.BulletinKind, pkt.BulletinID)
+		if pkt.BulletinGroup != "" {
+			tag = fmt.Sprintf("[%s %s %s]", pkt.BulletinKind, pkt.BulletinID, pkt.BulletinGroup)
+		}
+		age := ageStyle.Render(fmt.Sprintf("(%s)", now.Sub(e.received).Round(time.Minute)))
+		lines = append(lines, fmt.Sprintf("%s %-9s %s %s", tag, pkt.Callsign, pkt.MsgBody, age))
+	}
+	if len(lines) > contentHeight {
+		lines = lines[:contentHeight]
+	}
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(contentWidth).Render(line)
+	}
+
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -13,6 +13,9 @@ type Model struct {
 	mapShapePath string
 	zoomLevel    float64
 	lastPacket   string // --- NEW ---
+	labelMode    string
+	filter       string // APRS-IS filter set from the UI, if any
+	following    bool   // Filter follows the map view
 }
 
 // New creates a new footer model
@@ -22,6 +25,7 @@ func New(mapShapePath string) Model {
 		mapShapePath: mapShapePath,
 		zoomLevel:    1.0,
 		lastPacket:   "---", // --- NEW ---
+		labelMode:    "Call",
 	}
 }
 
@@ -40,6 +44,17 @@ func (m *Model) SetLastPacket(call string) {
 	m.lastPacket = call
 }
 
+// SetLabelMode allows the parent model to show the map label mode
+func (m *Model) SetLabelMode(mode string) {
+	m.labelMode = mode
+}
+
+// SetFilter shows the APRS-IS filter and whether it follows the map
+func (m *Model) SetFilter(filter string, following bool) {
+	m.filter = filter
+	m.following = following
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -56,12 +71,20 @@ func (m Model) View() string {
 	// --- UPDATED ---
 	// Show Last Packet and Zoom
 	footerLeft := footerStyle.Render(fmt.Sprintf(
-		"PacketMap | Last: %-9s | Zoom: %.1fx", // %-9s pads the callsign
+		"PacketMap | Last: %-9s | Zoom: %.1fx | Labels: %s", // %-9s pads the callsign
 		m.lastPacket,
 		m.zoomLevel,
+		m.labelMode,
 	))
+	if m.filter != "" {
+		follow := ""
+		if m.following {
+			follow = " (follow)"
+		}
+		footerLeft += footerStyle.Render(fmt.Sprintf("| Filter: %s%s", m.filter, follow))
+	}
 
-	footerHelp := "Pan: j/k/l/; | Zoom: K/L | Reset: r | Quit: q"
+	footerHelp := "Pan: j/k/l/; | Zoom: K/L | Labels: w | Telemetry: t | Bulletins: b | Raw: d | Filter: f/a | Msg: m | Beacon: p | Reset: r | Quit: q"
 
 	// Use the component's width
 	footerRight := footerStyle.Width(m.width - lipgloss.Width(footerLeft) - 1).
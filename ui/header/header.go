@@ -1,19 +1,45 @@
 package header
 
 import (
+	"sort"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// LinkLevel picks the colour a link's status is drawn in
+type LinkLevel int
+
+const (
+	LinkUp   LinkLevel = iota // Connected
+	LinkWait                  // Connecting or reconnecting
+	LinkDown                  // Given up
+)
+
+var linkColors = map[LinkLevel]lipgloss.Color{
+	LinkUp:   lipgloss.Color("10"), // Green
+	LinkWait: lipgloss.Color("11"), // Yellow
+	LinkDown: lipgloss.Color("9"),  // Red
+}
+
+// link is the last reported status of one interface
+type link struct {
+	status string
+	level  LinkLevel
+}
+
 // Model holds the header's state
 type Model struct {
 	width int
+	links map[string]link // Keyed by interface name
 }
 
 // New creates a new header model
 func New() Model {
 	return Model{
 		width: 80, // Default width, will be updated
+		links: make(map[string]link),
 	}
 }
 
@@ -21,6 +47,11 @@ func (m Model) Init() tea.Cmd {
 	return nil
 }
 
+// SetLink shows the connection status of an interface
+func (m *Model) SetLink(name, status string, level LinkLevel) {
+	m.links[name] = link{status: status, level: level}
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -29,16 +60,38 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// linksView renders every interface's status, in name order
+func (m Model) linksView() string {
+	names := make([]string, 0, len(m.links))
+	for name := range m.links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		l := m.links[name]
+		parts = append(parts, lipgloss.NewStyle().
+			Background(lipgloss.Color("63")).
+			Foreground(linkColors[l.level]).
+			Render(name+": "+l.status))
+	}
+	return strings.Join(parts, lipgloss.NewStyle().Background(lipgloss.Color("63")).Render(" | "))
+}
+
 func (m Model) View() string {
 	title := "PacketMap"
 
+	// Keep room for the title when link errors are long
+	links := lipgloss.NewStyle().MaxWidth(max(m.width-len(title)-2, 0)).Render(m.linksView())
+
 	// Style for the header
 	style := lipgloss.NewStyle().
 		Bold(true).
-		Background(lipgloss.Color("63")). // Purple background (matches map border)
-		Foreground(lipgloss.Color("255")). // White text
-		Width(m.width).                   // Full terminal width
-		Align(lipgloss.Center)             // Center the text
+		Background(lipgloss.Color("63")).       // Purple background (matches map border)
+		Foreground(lipgloss.Color("255")).      // White text
+		Width(m.width - lipgloss.Width(links)). // Full terminal width, less the link status
+		Align(lipgloss.Center)                  // Center the text
 
-	return style.Render(title)
-}
\ No newline at end of file
+	return lipgloss.JoinHorizontal(lipgloss.Top, style.Render(title), links)
+}
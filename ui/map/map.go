@@ -6,6 +6,7 @@ import (
 	"packetmap/config"
 	"packetmap/packet"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -32,6 +33,9 @@ type Model struct {
 	stationExists bool
 
 	plottedPackets []*packet.Packet
+	heard          map[string]time.Time // When each station was last heard
+	symbols        map[string]symbolStyle
+	labelMode      labelMode
 }
 
 // loadMapData reads the shapefile
@@ -81,6 +85,8 @@ func New(mapShapePath string, conf config.Config) (Model, error) {
 		height:         23,
 		stationExists:  false,
 		plottedPackets: make([]*packet.Packet, 0),
+		heard:          make(map[string]time.Time),
+		symbols:        buildSymbolTable(conf.Map.Symbols),
 	}
 
 	stationGrid := conf.Station.GridSquare
@@ -145,6 +151,16 @@ func (m Model) GetZoomLevel() float64 {
 	return (m.originalBounds.MaxX - m.originalBounds.MinX) / (m.viewBounds.MaxX - m.viewBounds.MinX)
 }
 
+// ViewBounds returns the area the map is showing, clamped to valid
+// coordinates: north and south latitude, west and east longitude
+func (m Model) ViewBounds() (north, west, south, east float64) {
+	clamp := func(v, limit float64) float64 {
+		return max(-limit, min(limit, v))
+	}
+	return clamp(m.viewBounds.MaxY, 90), clamp(m.viewBounds.MinX, 180),
+		clamp(m.viewBounds.MinY, 90), clamp(m.viewBounds.MaxX, 180)
+}
+
 // Update function
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -153,9 +169,45 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// log.Printf("Map Update: Received packet: %s (%.3f, %.3f)", msg.Callsign, msg.Lat, msg.Lon)
 		// --- END DEBUG LOG REMOVAL ---
 
+		if msg.Type == packet.TypeStatus {
+			m.plotLocator(msg)
+			return m, nil
+		}
+
+		// Positionless weather updates an already-plotted station
+		if !hasPosition(msg) {
+			for i, pkt := range m.plottedPackets {
+				if pkt.Callsign == msg.Callsign && msg.Weather != nil {
+					updated := *pkt
+					updated.Weather = msg.Weather
+					m.plottedPackets[i] = &updated
+					break
+				}
+			}
+			return m, nil
+		}
+
+		// A killed object or item comes off the map
+		if msg.Killed {
+			for i, pkt := range m.plottedPackets {
+				if pkt.Callsign == msg.Callsign {
+					m.plottedPackets = append(m.plottedPackets[:i], m.plottedPackets[i+1:]...)
+					delete(m.heard, msg.Callsign)
+					break
+				}
+			}
+			return m, nil
+		}
+
+		m.heard[msg.Callsign] = time.Now()
 		found := false
 		for i, pkt := range m.plottedPackets {
 			if pkt.Callsign == msg.Callsign {
+				// Ignore stale re-transmissions of an older fix
+				if !msg.Timestamp.IsZero() && msg.Timestamp.Before(pkt.Timestamp) {
+					found = true
+					break
+				}
 				m.plottedPackets[i] = msg
 				found = true
 				break
@@ -178,11 +230,41 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case "K": m.zoomByFactor(1 / zoomFactor)
 		case "L": m.zoomByFactor(zoomFactor)
 		case "r": m.viewBounds = m.originalBounds
+		case "w": m.labelMode = (m.labelMode + 1) % labelModes
 		}
 	}
 	return m, nil
 }
 
+// plotLocator places a station known only from a Maidenhead locator
+// status at the centre of its grid square. A station that has sent a
+// real position keeps it.
+func (m *Model) plotLocator(pkt *packet.Packet) {
+	if pkt.StatusLocator == "" {
+		return
+	}
+	for _, plotted := range m.plottedPackets {
+		if plotted.Callsign == pkt.Callsign && plotted.Type != packet.TypeStatus {
+			return
+		}
+	}
+	lon, lat, err := GridSquareToLatLon(pkt.StatusLocator)
+	if err != nil {
+		return
+	}
+
+	located := *pkt
+	located.Lat, located.Lon = lat, lon
+	m.heard[pkt.Callsign] = time.Now()
+	for i, plotted := range m.plottedPackets {
+		if plotted.Callsign == pkt.Callsign {
+			m.plottedPackets[i] = &located
+			return
+		}
+	}
+	m.plottedPackets = append(m.plottedPackets, &located)
+}
+
 // project converts lon/lat to terminal x/y coordinates
 func (m *Model) project(lon, lat float64, viewWidth, viewHeight int) (int, int) {
 	if m.viewBounds.MaxX == m.viewBounds.MinX { m.viewBounds.MaxX += 1e-6 }
@@ -200,8 +282,10 @@ func (m Model) renderMapViewport(viewWidth, viewHeight int) string {
 	if viewHeight <= 0 { viewHeight = 1 }
 
 	grid := make([][]rune, viewHeight)
+	colors := make([][]string, viewHeight) // Foreground colour per cell, "" for default
 	for i := range grid {
 		grid[i] = make([]rune, viewWidth)
+		colors[i] = make([]string, viewWidth)
 		for j := range grid[i] {
 			grid[i][j] = ' '
 		}
@@ -239,11 +323,21 @@ func (m Model) renderMapViewport(viewWidth, viewHeight int) string {
 		// --- END DEBUG LOG REMOVAL ---
 
 		if x >= 0 && x < viewWidth && y >= 0 && y < viewHeight {
-			grid[y][x] = '*' // Plot the packet position
+			// Plot the packet position using its APRS symbol
+			style := m.symbolFor(pkt)
+			grid[y][x] = style.glyph
+			colors[y][x] = style.color
+
+			// Overlay symbols (e.g. '#' with a digit) show the overlay
+			// character just after the glyph, unless something's there
+			if pkt.SymbolOverlay != 0 && x+1 < viewWidth && (grid[y][x+1] == ' ' || grid[y][x+1] == '.') {
+				grid[y][x+1] = rune(pkt.SymbolOverlay)
+				colors[y][x+1] = style.color
+			}
 
-			// Draw callsign UNDER the packet, if there's room
+			// Draw label (callsign or weather) UNDER the packet, if there's room
 			if y+1 < viewHeight {
-				callRunes := []rune(pkt.Callsign)
+				callRunes := []rune(m.labelFor(pkt))
 				startOffset := x - (len(callRunes) / 2)
 				for i := 0; i < len(callRunes); i++ {
 					plotX := startOffset + i
@@ -262,8 +356,20 @@ func (m Model) renderMapViewport(viewWidth, viewHeight int) string {
 	}
 
 	var b strings.Builder
-	for _, row := range grid {
-		b.WriteString(string(row))
+	for y, row := range grid {
+		// Render runs of same-coloured cells together
+		start := 0
+		for x := 1; x <= len(row); x++ {
+			if x < len(row) && colors[y][x] == colors[y][start] {
+				continue
+			}
+			run := string(row[start:x])
+			if colors[y][start] != "" {
+				run = lipgloss.NewStyle().Foreground(lipgloss.Color(colors[y][start])).Render(run)
+			}
+			b.WriteString(run)
+			start = x
+		}
 		b.WriteRune('\n')
 	}
 	return b.String()
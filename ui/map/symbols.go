@@ -0,0 +1,96 @@
+package mapview
+
+import (
+	"packetmap/config"
+	"packetmap/packet"
+)
+
+// symbolStyle is how one APRS symbol is drawn on the map
+type symbolStyle struct {
+	glyph rune
+	color string // lipgloss colour, "" for the terminal default
+}
+
+// defaultStation is used for any symbol not in the table
+var defaultStation = symbolStyle{glyph: '*', color: "15"}
+
+// defaultSymbols maps table + symbol code to a glyph and colour.
+// Keys use the same "/>" form as the [map.symbols] config table.
+var defaultSymbols = map[string]symbolStyle{
+	// Vehicles
+	"/>":  {'c', "11"}, // Car
+	"/<":  {'m', "11"}, // Motorcycle
+	"/b":  {'b', "11"}, // Bicycle
+	"/j":  {'j', "11"}, // Jeep
+	"/k":  {'t', "11"}, // Truck
+	"/u":  {'t', "11"}, // Truck (18 wheeler)
+	"/v":  {'v', "11"}, // Van
+	"/R":  {'r', "11"}, // Recreational vehicle
+	"/U":  {'u', "11"}, // Bus
+	"/f":  {'f', "9"},  // Fire truck
+	"/a":  {'+', "9"},  // Ambulance
+	"/!":  {'!', "9"},  // Police
+	"/[":  {'p', "14"}, // Person / jogger
+	"/O":  {'o', "13"}, // Balloon
+	"/'":  {'a', "13"}, // Small aircraft
+	"/^":  {'A', "13"}, // Large aircraft
+	"/X":  {'x', "13"}, // Helicopter
+	"/Y":  {'y', "12"}, // Yacht
+	"/s":  {'s', "12"}, // Ship
+	"\\^": {'A', "13"}, // Aircraft (alternate)
+	"\\>": {'c', "11"}, // Car (alternate / overlay)
+
+	// Fixed stations
+	"/-":  {'h', "10"}, // House
+	"/y":  {'h', "10"}, // House with yagi
+	"/#":  {'#', "14"}, // Digipeater
+	"\\#": {'#', "14"}, // Digipeater (overlay)
+	"/&":  {'&', "14"}, // HF gateway
+	"\\&": {'I', "14"}, // Igate / gateway (overlay)
+	"/r":  {'R', "14"}, // Repeater
+	"/n":  {'n', "14"}, // Node
+	"/_":  {'W', "12"}, // Weather station
+	"\\_": {'W', "12"}, // Weather station (overlay)
+	"/W":  {'W', "12"}, // National Weather Service site
+	"/h":  {'+', "9"},  // Hospital
+	"/o":  {'E', "9"},  // EOC
+	"/;":  {'^', "10"}, // Campground
+	"\\n": {'!', "9"},  // Red triangle / emergency
+}
+
+// buildSymbolTable merges the user's [map.symbols] config on top of the
+// defaults. Entries with an empty glyph keep the default glyph, so a
+// config line may change just the colour.
+func buildSymbolTable(overrides map[string]config.SymbolStyle) map[string]symbolStyle {
+	table := make(map[string]symbolStyle, len(defaultSymbols)+len(overrides))
+	for k, v := range defaultSymbols {
+		table[k] = v
+	}
+
+	for k, v := range overrides {
+		style, ok := table[k]
+		if !ok {
+			style = defaultStation
+		}
+		if glyph := []rune(v.Glyph); len(glyph) > 0 {
+			style.glyph = glyph[0]
+		}
+		if v.Color != "" {
+			style.color = v.Color
+		}
+		table[k] = style
+	}
+	return table
+}
+
+// symbolFor looks up the style for a packet's symbol.
+func (m Model) symbolFor(pkt *packet.Packet) symbolStyle {
+	if pkt.SymbolTable == 0 {
+		return defaultStation
+	}
+	key := string([]byte{pkt.SymbolTable, pkt.Symbol})
+	if style, ok := m.symbols[key]; ok {
+		return style
+	}
+	return defaultStation
+}
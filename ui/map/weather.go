@@ -0,0 +1,88 @@
+package mapview
+
+import (
+	"fmt"
+	"packetmap/packet"
+)
+
+// labelMode selects what is drawn under each station
+type labelMode int
+
+const (
+	labelCallsign    labelMode = iota // Callsign for every station
+	labelTemperature                  // Temperature for weather stations
+	labelWind                         // Wind direction/speed for weather stations
+	labelTime                         // When heard, and when the position was fixed if sent
+
+	labelModes = iota // Number of modes, for cycling
+)
+
+// String returns the mode name shown in the footer
+func (l labelMode) String() string {
+	switch l {
+	case labelTemperature:
+		return "Temp"
+	case labelWind:
+		return "Wind"
+	case labelTime:
+		return "Time"
+	default:
+		return "Call"
+	}
+}
+
+// LabelMode returns the name of the current label mode
+func (m Model) LabelMode() string {
+	return m.labelMode.String()
+}
+
+// labelFor returns the text drawn under a station for the current mode.
+// Stations without the requested weather data keep their callsign.
+func (m Model) labelFor(pkt *packet.Packet) string {
+	if m.labelMode == labelTime {
+		return m.timeLabel(pkt)
+	}
+
+	wx := pkt.Weather
+	if wx == nil {
+		return pkt.Callsign
+	}
+
+	switch m.labelMode {
+	case labelTemperature:
+		if wx.Temperature != nil {
+			return fmt.Sprintf("%.0fF", *wx.Temperature)
+		}
+	case labelWind:
+		if wx.WindSpeed != nil {
+			label := fmt.Sprintf("%.0fmph", *wx.WindSpeed)
+			if wx.WindDirection != nil {
+				label = fmt.Sprintf("%03.0f@%s", *wx.WindDirection, label)
+			}
+			if wx.WindGust != nil {
+				label += fmt.Sprintf("g%.0f", *wx.WindGust)
+			}
+			return label
+		}
+	}
+	return pkt.Callsign
+}
+
+// timeLabel shows when a station was heard and, if its report carried
+// a timestamp, when the position was actually fixed (both UTC)
+func (m Model) timeLabel(pkt *packet.Packet) string {
+	label := pkt.Callsign
+	if heard, ok := m.heard[pkt.Callsign]; ok {
+		label = heard.UTC().Format("15:04z")
+	}
+	if !pkt.Timestamp.IsZero() {
+		label += " fixed " + pkt.Timestamp.UTC().Format("15:04z")
+	}
+	return label
+}
+
+// hasPosition reports whether a packet carries a position. Every
+// position decoder sets a symbol, positionless weather doesn't.
+func hasPosition(pkt *packet.Packet) bool {
+	return pkt.SymbolTable != 0
+}
@@ -13,11 +13,49 @@ const (
 	barHeight = 7// Total height of the component (including border)
 )
 
+// deliveryStatus tracks whether a message has been acknowledged
+type deliveryStatus int
+
+const (
+	statusNone     deliveryStatus = iota // No message ID, so no ack expected
+	statusPending                        // Waiting for an ack
+	statusAcked                          // Acknowledged by the recipient
+	statusRejected                       // Rejected by the recipient
+)
+
+// message is one line in the bar
+type message struct {
+	from   string
+	to     string
+	body   string
+	id     string
+	status deliveryStatus
+	notice bool // A note from PacketMap itself, e.g. a failed send
+}
+
+// String formats the message with its delivery status
+// Example: N0CALL>KD2YCB: Hello world! [ack]
+func (msg message) String() string {
+	if msg.notice {
+		return "-- " + msg.body
+	}
+	line := fmt.Sprintf("%s>%s: %s", msg.from, msg.to, msg.body)
+	switch msg.status {
+	case statusPending:
+		line += " [...]"
+	case statusAcked:
+		line += " [ack]"
+	case statusRejected:
+		line += " [rej]"
+	}
+	return line
+}
+
 // Model holds the message bar's state
 type Model struct {
 	width    int
 	height   int
-	messages []string // Stores formatted message strings
+	messages []message // Newest first
 }
 
 // New creates a new message bar model
@@ -25,7 +63,41 @@ func New() Model {
 	return Model{
 		width:    80,
 		height:   barHeight,
-		messages: make([]string, 0),
+		messages: make([]message, 0),
+	}
+}
+
+// setStatus marks the message from -> to with the given ID
+func (m *Model) setStatus(from, to, id string, status deliveryStatus) {
+	if id == "" {
+		return
+	}
+	for i := range m.messages {
+		msg := &m.messages[i]
+		if msg.from == from && msg.to == to && msg.id == id {
+			msg.status = status
+			return
+		}
+	}
+}
+
+// AddNotice shows a note from PacketMap itself in the bar
+func (m *Model) AddNotice(text string) {
+	m.push(message{body: text, notice: true})
+}
+
+// push adds a line to the top, trimming the list to what fits
+func (m *Model) push(line message) {
+	m.messages = append([]message{line}, m.messages...)
+
+	// Trim the list if it's too long
+	// barHeight - 2 (for borders)
+	maxMessages := barHeight - 2
+	if maxMessages < 1 {
+		maxMessages = 1
+	}
+	if len(m.messages) > maxMessages {
+		m.messages = m.messages[:maxMessages]
 	}
 }
 
@@ -46,22 +118,41 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil // Should not happen, but good to check
 		}
 
-		// Format the message
-		// Example: N0CALL>KD2YCB: Hello world!
-		line := fmt.Sprintf("%s>%s: %s", msg.Callsign, msg.MsgTo, msg.MsgBody)
+		// An ack or reject answers a message we already have: the
+		// original went from the ack's addressee to the ack's sender
+		switch msg.MsgKind {
+		case packet.MessageAck:
+			m.setStatus(msg.MsgTo, msg.Callsign, msg.MsgID, statusAcked)
+			return m, nil
+		case packet.MessageReject:
+			m.setStatus(msg.MsgTo, msg.Callsign, msg.MsgID, statusRejected)
+			return m, nil
+		}
 
-		// Add to the top
-		m.messages = append([]string{line}, m.messages...)
+		// A reply-ack acknowledges an earlier message in the other direction
+		m.setStatus(msg.MsgTo, msg.Callsign, msg.MsgReplyAck, statusAcked)
 
-		// Trim the list if it's too long
-		// barHeight - 2 (for borders)
-		maxMessages := barHeight - 2
-		if maxMessages < 1 {
-			maxMessages = 1
+		line := message{
+			from: msg.Callsign,
+			to:   msg.MsgTo,
+			body: msg.MsgBody,
+			id:   msg.MsgID,
 		}
-		if len(m.messages) > maxMessages {
-			m.messages = m.messages[:maxMessages]
+		if line.id != "" {
+			line.status = statusPending
 		}
+
+		// Retries of a message we already show replace it in place
+		for i, existing := range m.messages {
+			if line.id != "" && existing.from == line.from && existing.to == line.to && existing.id == line.id {
+				line.status = existing.status
+				m.messages = append(m.messages[:i], m.messages[i+1:]...)
+				break
+			}
+		}
+
+		// Add to the top
+		m.push(line)
 	}
 	return m, nil
 }
@@ -93,7 +184,7 @@ func (m Model) View() string {
 		if i < len(m.messages) {
 			// Get message from bottom up to show oldest first in bar
 			// This shows messages in the order they arrived
-			msg := m.messages[len(m.messages)-1-i]
+			msg := m.messages[len(m.messages)-1-i].String()
 
 			// Truncate
 			if len(msg) > contentWidth {
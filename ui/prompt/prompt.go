@@ -0,0 +1,89 @@
+package prompt
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SubmitMsg is sent when the user presses enter
+type SubmitMsg struct {
+	ID    string // Which prompt it came from
+	Value string
+}
+
+var (
+	labelStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63"))
+	cursorStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// Model is a one-line text input, shown in place of the footer while open
+type Model struct {
+	width  int
+	active bool
+	id     string
+	label  string
+	value  []rune
+}
+
+// New creates a closed prompt
+func New() Model {
+	return Model{width: 80}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Open shows the prompt with value pre-filled. id is returned in the
+// SubmitMsg so the caller knows what was being asked.
+func (m *Model) Open(id, label, value string) {
+	m.active = true
+	m.id = id
+	m.label = label
+	m.value = []rune(value)
+}
+
+// Active reports whether the prompt is open and wants the keyboard
+func (m Model) Active() bool {
+	return m.active
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		if !m.active {
+			return m, nil
+		}
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.active = false
+			submit := SubmitMsg{ID: m.id, Value: string(m.value)}
+			return m, func() tea.Msg { return submit }
+		case tea.KeyEsc, tea.KeyCtrlC:
+			m.active = false
+		case tea.KeyBackspace:
+			if len(m.value) > 0 {
+				m.value = m.value[:len(m.value)-1]
+			}
+		case tea.KeyCtrlU:
+			m.value = m.value[:0]
+		case tea.KeySpace:
+			m.value = append(m.value, ' ')
+		case tea.KeyRunes:
+			m.value = append(m.value, msg.Runes...)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	line := labelStyle.Render(m.label+": ") + string(m.value) + cursorStyle.Render(" ")
+	return lipgloss.NewStyle().
+		Width(m.width).
+		MaxWidth(m.width).
+		Padding(0, 1).
+		Render(line)
+}
@@ -0,0 +1,109 @@
+package rawlog
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxRecords is how many received frames we keep
+const maxRecords = 200
+
+var (
+	timeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// Model holds the raw frame log's state
+type Model struct {
+	width   int
+	height  int
+	records []*packet.Record // Newest first
+}
+
+// New creates a new raw frame log
+func New() Model {
+	return Model{
+		width:   80,
+		height:  23,
+		records: make([]*packet.Record, 0),
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// AddRecord adds a received frame to the top of the log
+func (m *Model) AddRecord(rec *packet.Record) {
+	m.records = append([]*packet.Record{rec}, m.records...)
+	if len(m.records) > maxRecords {
+		m.records = m.records[:maxRecords]
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// printable replaces control characters so binary payloads can't
+// upset the terminal
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return '.'
+		}
+		return r
+	}, s)
+}
+
+func (m Model) View() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Width(m.width-2).   // -2 for border
+		Height(m.height-2). // -2 for border
+		Padding(0, 1)
+
+	contentWidth := m.width - 2 - 2 // -border, -padding
+	contentHeight := m.height - 2
+	if contentWidth < 1 {
+		contentWidth = 1
+	}
+	if contentHeight < 0 {
+		contentHeight = 0
+	}
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Underline(true).Render("Raw Frames")}
+	for _, rec := range m.records {
+		if len(lines) >= contentHeight {
+			break
+		}
+		source := rec.Interface
+		if rec.Port != 0 {
+			source += fmt.Sprintf(" port %d", rec.Port)
+		}
+		lines = append(lines, timeStyle.Render(rec.Received.Format("15:04:05")+" "+source)+" "+printable(rec.TNC2))
+		if rec.Err != nil && len(lines) < contentHeight {
+			lines = append(lines, errorStyle.Render("  ! "+rec.Err.Error()))
+		}
+	}
+	if len(lines) > contentHeight {
+		lines = lines[:contentHeight]
+	}
+
+	// Truncate each line so the box doesn't wrap
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(contentWidth).Render(line)
+	}
+
+	return style.Render(strings.Join(lines, "\n"))
+}
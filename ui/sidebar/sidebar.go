@@ -8,11 +8,16 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// maxStatusLines is how many lines a callsign's status may wrap onto
+const maxStatusLines = 2
+
 // Model holds the sidebar's state
 type Model struct {
 	width   int
 	height  int
 	packets []string // A list of callsigns
+
+	statuses map[string]string // Latest status text per callsign
 }
 
 // New creates a new sidebar model
@@ -21,6 +26,8 @@ func New() Model {
 		width:   20, // Default
 		height:  24, // Default
 		packets: make([]string, 0),
+
+		statuses: make(map[string]string),
 	}
 }
 
@@ -46,6 +53,11 @@ func (m *Model) AddPacket(callsign string) {
 	}
 }
 
+// SetStatus records the latest status text for a callsign
+func (m *Model) SetStatus(callsign, status string) {
+	m.statuses[callsign] = status
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -65,6 +77,29 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// entryLines renders the callsign list, each callsign followed by its
+// status (if we have one) on lines of its own, wrapped to width
+func (m Model) entryLines(width int) []string {
+	if width < 1 {
+		return nil
+	}
+	statusStyle := lipgloss.NewStyle().Faint(true)
+
+	var lines []string
+	for _, call := range m.packets {
+		// Truncate to fit width
+		lines = append(lines, fmt.Sprintf("%.*s", width, call))
+
+		status := []rune(m.statuses[call])
+		for row := 0; row < maxStatusLines && len(status) > 0 && width > 1; row++ {
+			n := min(len(status), width-1)
+			lines = append(lines, statusStyle.Render(" "+string(status[:n])))
+			status = status[n:]
+		}
+	}
+	return lines
+}
+
 func (m Model) View() string {
 	style := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -94,18 +129,12 @@ func (m Model) View() string {
 	}
 
 	if contentHeight > 0 { // Only add packets if there is space
-		b.WriteRune('\n') // Add newline after header
-		for i, call := range m.packets {
-			if i >= contentHeight {
-				break // Stop if we run out of room
-			}
-			// Truncate callsign to fit width
-			line := fmt.Sprintf("%.*s", m.width-2-2, call)
-			b.WriteString(line)
-			if i < len(m.packets)-1 && i < contentHeight-1 { // Don't add newline to the very last item
-				b.WriteRune('\n')
-			}
+		lines := m.entryLines(m.width - 2 - 2)
+		if len(lines) > contentHeight {
+			lines = lines[:contentHeight] // Stop if we run out of room
 		}
+		b.WriteRune('\n') // Add newline after header
+		b.WriteString(strings.Join(lines, "\n"))
 	}
 
 	// Now, `b.String()` is a single string that has *at most*
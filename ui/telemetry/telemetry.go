@@ -0,0 +1,171 @@
+package telemetryview
+
+import (
+	"fmt"
+	"packetmap/packet"
+	"packetmap/telemetry"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// historySize is how many telemetry frames we keep per station
+const historySize = 100
+
+// sparkRunes draw a channel's history, lowest to highest
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// Model holds the telemetry panel's state
+type Model struct {
+	width  int
+	height int
+	store  *telemetry.Store
+}
+
+// New creates a new telemetry panel
+func New() Model {
+	return Model{
+		width:  80,
+		height: 23,
+		store:  telemetry.NewStore(historySize),
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// AddPacket records a TypeTelemetry packet
+func (m *Model) AddPacket(pkt *packet.Packet) {
+	m.store.Add(pkt)
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// sparkline renders the scaled history of one analog channel
+func sparkline(history []telemetry.Sample, channel, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(history) > width {
+		history = history[len(history)-width:]
+	}
+
+	var values []float64
+	for _, s := range history {
+		if channel < len(s.Values) {
+			values = append(values, s.Values[channel])
+		}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparkRunes)-1))
+		}
+		b.WriteRune(sparkRunes[idx])
+	}
+	return b.String()
+}
+
+// stationLines renders one station's block
+func stationLines(st *telemetry.Station, width int) []string {
+	def := st.Definition
+	title := st.Callsign
+	if def.Project != "" {
+		title += " - " + def.Project
+	}
+
+	latest := st.Latest()
+	if latest == nil {
+		return []string{title + " (definitions only)"}
+	}
+	title += fmt.Sprintf(" (#%s, %s ago)", latest.Sequence, time.Since(latest.Time).Round(time.Second))
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(title)}
+
+	for ch, v := range latest.Values {
+		name := def.Names[ch]
+		if name == "" {
+			name = fmt.Sprintf("A%d", ch+1)
+		}
+		label := fmt.Sprintf("  %-12.12s %10.2f %-6.6s ", name, v, def.Units[ch])
+		lines = append(lines, label+sparkline(st.History, ch, max(width-len(label), 0)))
+	}
+
+	if latest.HasDigital {
+		var bits []string
+		for i, on := range latest.Digital {
+			name := def.Names[5+i]
+			if name == "" {
+				name = fmt.Sprintf("B%d", i+1)
+			}
+			state := "off"
+			if on == def.Sense[i] {
+				state = "ON"
+			}
+			bits = append(bits, name+":"+state)
+		}
+		lines = append(lines, "  "+strings.Join(bits, " "))
+	}
+	return lines
+}
+
+func (m Model) View() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Width(m.width-2).   // -2 for border
+		Height(m.height-2). // -2 for border
+		Padding(0, 1)
+
+	contentWidth := m.width - 2 - 2 // -border, -padding
+	contentHeight := m.height - 2
+	if contentWidth < 1 {
+		contentWidth = 1
+	}
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Underline(true).Render("Telemetry")}
+	stations := m.store.Stations()
+	if len(stations) == 0 {
+		lines = append(lines, "No telemetry heard yet")
+	}
+	for _, st := range stations {
+		lines = append(lines, stationLines(st, contentWidth)...)
+	}
+
+	// Only show what fits; the box must not grow
+	if contentHeight < 0 {
+		contentHeight = 0
+	}
+	if len(lines) > contentHeight {
+		lines = lines[:contentHeight]
+	}
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(contentWidth).Render(line)
+	}
+
+	return style.Render(strings.Join(lines, "\n"))
+}